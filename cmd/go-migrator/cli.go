@@ -0,0 +1,326 @@
+// File: cmd/go-migrator/cli.go
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hashirventhodi/go-migrator/migrator"
+	"github.com/spf13/cobra"
+)
+
+// version is printed by `migrator version` and `migrator --version`.
+const version = "0.0.3"
+
+var (
+	databaseURL      string
+	sslMode          string
+	sslRootCert      string
+	sslCert          string
+	sslKey           string
+	connectTimeout   time.Duration
+	statementTimeout time.Duration
+
+	dbHost     string
+	dbPort     int
+	dbUser     string
+	dbPassword string
+	dbName     string
+	outputDir  string
+	debug      bool
+	configFile string
+	envName    string
+
+	modelPatterns []string
+
+	rollbackSteps int
+	rollbackTo    string
+	statusFormat  string
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "migrator",
+	Short: "Migrator is a tool for generating database migrations",
+	Long: `Migrator is a CLI tool that generates database migrations
+based on your GORM models. It compares your models with the
+current database schema and creates migration files for any
+differences it finds.`,
+	Version: version,
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the version of the CLI",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Printf("go-migrator version %s\n", version)
+	},
+}
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate migration files",
+	Long:  `Generate migration files based on the differences between your models and the current database schema.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// This binary registers no models of its own, so they always come
+		// from the packages given with --models.
+		err := fmt.Errorf("no models given; pass the packages that define them with --models")
+		if len(modelPatterns) > 0 {
+			err = generateForPackages(cmd)
+		}
+		if err != nil {
+			fmt.Printf("Failed to generate migrations: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("Migrations generated successfully!")
+	},
+}
+
+// generateForPackages discovers the models in the packages given with
+// --models and generates migrations for them.
+func generateForPackages(cmd *cobra.Command) error {
+	config, err := configFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	discovery, err := migrator.DiscoverModels(".", modelPatterns...)
+	if err != nil {
+		return err
+	}
+	if debug {
+		for _, model := range discovery.Models {
+			log.Printf("Discovered model: %s", model)
+		}
+	}
+	return discovery.Generate(commandContext(cmd), config)
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply pending migrations",
+	Long:  `Apply every up migration in the output directory that has not been recorded in the schema_migrations table yet.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		m := newMigratorFromFlags(cmd)
+
+		err := m.Apply(commandContext(cmd))
+		if err != nil {
+			fmt.Printf("Failed to apply migrations: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("Migrations applied successfully!")
+	},
+}
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Roll back applied migrations",
+	Long:  `Roll back the most recently applied migrations by running their down files in reverse order.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		m := newMigratorFromFlags(cmd)
+
+		var err error
+		if cmd.Flags().Changed("to") {
+			err = m.RollbackTo(commandContext(cmd), rollbackTo)
+		} else {
+			err = m.Rollback(commandContext(cmd), rollbackSteps)
+		}
+		if err != nil {
+			fmt.Printf("Failed to roll back migrations: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("Rollback completed successfully!")
+	},
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show migration status",
+	Long:  `Show which migrations are applied, pending, missing on disk, or modified since they were applied.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		m := newMigratorFromFlags(cmd)
+
+		report, err := m.Status(commandContext(cmd))
+		if err != nil {
+			fmt.Printf("Failed to get migration status: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch statusFormat {
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(report)
+		case "table":
+			err = printStatusTable(report)
+		default:
+			err = fmt.Errorf("unknown format %q, expected table or json", statusFormat)
+		}
+		if err != nil {
+			fmt.Printf("Failed to print migration status: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func printStatusTable(report *migrator.StatusReport) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, s := range report.Migrations {
+		appliedAt := "-"
+		if s.AppliedAt != nil {
+			appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Version, s.Name, s.State, appliedAt)
+	}
+	fmt.Fprintf(w, "\n%d applied, %d pending, %d missing, %d modified\n",
+		report.Count(migrator.StateApplied), report.Count(migrator.StatePending), report.Count(migrator.StateMissing), report.Count(migrator.StateModified))
+	return w.Flush()
+}
+
+func init() {
+	rootCmd.SetVersionTemplate("go-migrator version {{.Version}}\n")
+
+	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(generateCmd)
+	rootCmd.AddCommand(applyCmd)
+	rootCmd.AddCommand(rollbackCmd)
+	rootCmd.AddCommand(statusCmd)
+
+	addConnectionFlags(generateCmd)
+	addConnectionFlags(applyCmd)
+	addConnectionFlags(rollbackCmd)
+	addConnectionFlags(statusCmd)
+
+	rollbackCmd.Flags().IntVar(&rollbackSteps, "steps", 1, "Number of migrations to roll back")
+	rollbackCmd.Flags().StringVar(&rollbackTo, "to", "", "Roll back every migration applied after this version")
+	rollbackCmd.MarkFlagsMutuallyExclusive("steps", "to")
+
+	generateCmd.Flags().StringSliceVar(&modelPatterns, "models", nil, "Discover models in these packages, e.g. ./internal/models/...")
+
+	statusCmd.Flags().StringVar(&statusFormat, "format", "table", "Output format: table or json")
+}
+
+// addConnectionFlags registers the database and output flags shared by
+// every subcommand. Each of them can also be set in the config file or
+// with a MIGRATOR_* environment variable.
+func addConnectionFlags(cmd *cobra.Command) {
+	defaults := migrator.DefaultConfig()
+	cmd.Flags().StringVar(&configFile, "config", "", "Config file (default migrator.yaml, migrator.yml or migrator.toml)")
+	cmd.Flags().StringVar(&envName, "env", "", "Environment profile from the config file, e.g. staging")
+	cmd.Flags().StringVar(&databaseURL, "database-url", "", "Database URL (postgres://...) or key/value DSN; replaces --host, --port, --user, --password and --dbname")
+	cmd.Flags().StringVar(&dbHost, "host", defaults.DBHost, "Database host or Unix socket directory")
+	cmd.Flags().IntVar(&dbPort, "port", defaults.DBPort, "Database port")
+	cmd.Flags().StringVar(&dbUser, "user", "", "Database user")
+	cmd.Flags().StringVar(&dbPassword, "password", "", "Database password (prefer MIGRATOR_PASSWORD or the config file)")
+	cmd.Flags().StringVar(&dbName, "dbname", "", "Database name")
+	cmd.Flags().StringVar(&sslMode, "sslmode", "", "SSL mode: disable, allow, prefer, require, verify-ca or verify-full (default disable without --database-url)")
+	cmd.Flags().StringVar(&sslRootCert, "sslrootcert", "", "CA certificate file for verifying the server")
+	cmd.Flags().StringVar(&sslCert, "sslcert", "", "Client certificate file")
+	cmd.Flags().StringVar(&sslKey, "sslkey", "", "Client private key file")
+	cmd.Flags().DurationVar(&connectTimeout, "connect-timeout", 0, "Timeout for establishing the connection, e.g. 10s")
+	cmd.Flags().DurationVar(&statementTimeout, "statement-timeout", 0, "Timeout for each statement, e.g. 5m")
+	cmd.Flags().StringVar(&outputDir, "output", defaults.OutputDir, "Output directory for migration files")
+	cmd.Flags().BoolVar(&debug, "debug", false, "Enable debug mode")
+}
+
+// configFromFlags loads the config file and MIGRATOR_* environment
+// variables, then applies the flags that were set explicitly, which take
+// precedence over both.
+func configFromFlags(cmd *cobra.Command) (migrator.Config, error) {
+	config, err := migrator.LoadConfig(configFile, envName)
+	if err != nil {
+		return migrator.Config{}, err
+	}
+
+	flags := cmd.Flags()
+	if flags.Changed("database-url") {
+		config.DatabaseURL = databaseURL
+	}
+	if flags.Changed("host") {
+		config.DBHost = dbHost
+	}
+	if flags.Changed("port") {
+		config.DBPort = dbPort
+	}
+	if flags.Changed("user") {
+		config.DBUser = dbUser
+	}
+	if flags.Changed("password") {
+		config.DBPassword = dbPassword
+	}
+	if flags.Changed("dbname") {
+		config.DBName = dbName
+	}
+	if flags.Changed("sslmode") {
+		config.SSLMode = sslMode
+	}
+	if flags.Changed("sslrootcert") {
+		config.SSLRootCert = sslRootCert
+	}
+	if flags.Changed("sslcert") {
+		config.SSLCert = sslCert
+	}
+	if flags.Changed("sslkey") {
+		config.SSLKey = sslKey
+	}
+	if flags.Changed("connect-timeout") {
+		config.ConnectTimeout = connectTimeout
+	}
+	if flags.Changed("statement-timeout") {
+		config.StatementTimeout = statementTimeout
+	}
+	if flags.Changed("output") {
+		config.OutputDir = outputDir
+	}
+	if flags.Changed("debug") {
+		config.Debug = debug
+	}
+	debug = config.Debug
+
+	if err := config.Validate(); err != nil {
+		return migrator.Config{}, err
+	}
+	return config, nil
+}
+
+func newMigratorFromFlags(cmd *cobra.Command) *migrator.Migrator {
+	config, err := configFromFlags(cmd)
+	if err != nil {
+		fmt.Printf("Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	m, err := migrator.New(config)
+	if err != nil {
+		fmt.Printf("Failed to create migrator: %v\n", err)
+		os.Exit(1)
+	}
+	return m
+}
+
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// RunCLI starts the CLI application. An interrupt cancels the command's
+// context, so running migrations and the registration program stop and
+// clean up after themselves.
+func RunCLI() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return rootCmd.ExecuteContext(ctx)
+}
@@ -0,0 +1,15 @@
+// File: cmd/go-migrator/main.go
+
+// Command go-migrator generates, applies and rolls back migrations for the
+// models registered with the migrator package.
+package main
+
+import (
+	"os"
+)
+
+func main() {
+	if err := RunCLI(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,182 @@
+// File: migrator/apply.go
+
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const historyTable = "schema_migrations"
+
+type appliedMigration struct {
+	Version   string
+	Name      string
+	Checksum  string
+	Duration  time.Duration
+	AppliedAt time.Time
+}
+
+func (m *Migrator) ensureHistoryTable(ctx context.Context) error {
+	_, err := m.sqlDB.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+historyTable+` (
+version VARCHAR(255) PRIMARY KEY,
+name TEXT NOT NULL,
+checksum VARCHAR(64) NOT NULL,
+duration_ms BIGINT NOT NULL,
+applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create %s table: %v", historyTable, err)
+	}
+	return nil
+}
+
+// historyTableExists reports whether the history table exists, without
+// creating it.
+func (m *Migrator) historyTableExists(ctx context.Context) (bool, error) {
+	var exists bool
+	err := m.sqlDB.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", historyTable).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to read migration history: %v", err)
+	}
+	return exists, nil
+}
+
+// appliedMigrations returns the recorded migrations ordered by version.
+func (m *Migrator) appliedMigrations(ctx context.Context) ([]appliedMigration, error) {
+	rows, err := m.sqlDB.QueryContext(ctx, "SELECT version, name, checksum, duration_ms, applied_at FROM "+historyTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration history: %v", err)
+	}
+	defer rows.Close()
+
+	var applied []appliedMigration
+	for rows.Next() {
+		var a appliedMigration
+		var durationMS int64
+		if err := rows.Scan(&a.Version, &a.Name, &a.Checksum, &durationMS, &a.AppliedAt); err != nil {
+			return nil, fmt.Errorf("failed to read migration history: %v", err)
+		}
+		a.Duration = time.Duration(durationMS) * time.Millisecond
+		applied = append(applied, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read migration history: %v", err)
+	}
+
+	sort.Slice(applied, func(i, j int) bool {
+		return compareVersions(applied[i].Version, applied[j].Version) < 0
+	})
+	return applied, nil
+}
+
+// Apply runs every up migration in the output directory that has not been
+// recorded in the history table yet, oldest first.
+func (m *Migrator) Apply(ctx context.Context) error {
+	if err := m.ensureHistoryTable(ctx); err != nil {
+		return err
+	}
+
+	migrations, err := readMigrations(m.config.OutputDir)
+	if err != nil {
+		return err
+	}
+
+	applied, err := m.appliedMigrations(ctx)
+	if err != nil {
+		return err
+	}
+	done := make(map[string]bool, len(applied))
+	for _, a := range applied {
+		done[a.Version] = true
+	}
+
+	pending := 0
+	for _, mig := range migrations {
+		if done[mig.Version] {
+			continue
+		}
+		pending++
+		if err := m.applyMigration(ctx, mig); err != nil {
+			return err
+		}
+	}
+
+	if pending == 0 {
+		fmt.Println("No pending migrations")
+	}
+	return nil
+}
+
+func (m *Migrator) applyMigration(ctx context.Context, mig Migration) error {
+	if m.config.Debug {
+		fmt.Printf("Applying %s:\n%s\n", mig.filename("up"), mig.Up)
+	}
+
+	start := time.Now()
+	err := m.runMigration(ctx, mig.Version, mig.Up, func(exec execer) error {
+		if err := execScript(ctx, exec, mig.Up); err != nil {
+			return fmt.Errorf("failed to apply migration %s_%s: %v", mig.Version, mig.Name, err)
+		}
+
+		_, err := exec.ExecContext(ctx,
+			"INSERT INTO "+historyTable+" (version, name, checksum, duration_ms, applied_at) VALUES ($1, $2, $3, $4, $5)",
+			mig.Version, mig.Name, checksum([]byte(mig.Up)), time.Since(start).Milliseconds(), time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to record migration %s: %v", mig.Version, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Applied migration: %s_%s (%s)\n", mig.Version, mig.Name, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// execer is implemented by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// runMigration calls run with a transaction that is committed afterwards,
+// or with the database itself when script carries noTransactionDirective.
+func (m *Migrator) runMigration(ctx context.Context, version, script string, run func(execer) error) error {
+	if !inTransaction(script) {
+		return run(m.sqlDB)
+	}
+
+	tx, err := m.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction for %s: %v", version, err)
+	}
+	defer tx.Rollback()
+
+	if err := run(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %v", version, err)
+	}
+	return nil
+}
+
+// execScript runs a migration script. Outside a transaction, statements are
+// sent one at a time: Postgres wraps a multi-statement query in an implicit
+// transaction.
+func execScript(ctx context.Context, exec execer, script string) error {
+	if inTransaction(script) {
+		_, err := exec.ExecContext(ctx, script)
+		return err
+	}
+	for _, stmt := range splitStatements(script) {
+		if _, err := exec.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+// File: migrator/config.go
+
+package migrator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the connection and output settings of a Migrator. The
+// yaml and toml keys are the names of the matching CLI flags.
+type Config struct {
+	// DatabaseURL is a postgres:// URL or a key/value DSN. When set, it
+	// replaces DBHost, DBPort and DBName, and DBUser and DBPassword only
+	// fill in a user or password it leaves out.
+	DatabaseURL string `yaml:"database_url" toml:"database_url"`
+
+	DBHost     string `yaml:"host" toml:"host"`
+	DBPort     int    `yaml:"port" toml:"port"`
+	DBUser     string `yaml:"user" toml:"user"`
+	DBPassword string `yaml:"password" toml:"password"`
+	DBName     string `yaml:"dbname" toml:"dbname"`
+
+	// SSLMode defaults to disable, unless DatabaseURL is set, in which
+	// case the URL's own sslmode or the driver default applies.
+	SSLMode     string `yaml:"sslmode" toml:"sslmode"`
+	SSLRootCert string `yaml:"sslrootcert" toml:"sslrootcert"`
+	SSLCert     string `yaml:"sslcert" toml:"sslcert"`
+	SSLKey      string `yaml:"sslkey" toml:"sslkey"`
+
+	// ConnectTimeout is rounded up to whole seconds. StatementTimeout
+	// applies to every statement, including migrations.
+	ConnectTimeout   time.Duration `yaml:"connect_timeout" toml:"connect_timeout"`
+	StatementTimeout time.Duration `yaml:"statement_timeout" toml:"statement_timeout"`
+
+	OutputDir string `yaml:"output" toml:"output"`
+	Debug     bool   `yaml:"debug" toml:"debug"`
+}
+
+// DefaultConfig returns the settings used when nothing else is given.
+func DefaultConfig() Config {
+	return Config{
+		DBHost:    "localhost",
+		DBPort:    5432,
+		OutputDir: "migrations",
+	}
+}
+
+// configFiles are looked up in the current directory, in order, when no
+// config file is given.
+var configFiles = []string{"migrator.yaml", "migrator.yml", "migrator.toml"}
+
+// envPrefix is the prefix of the environment variables that override the
+// config file, e.g. MIGRATOR_PASSWORD.
+const envPrefix = "MIGRATOR_"
+
+// LoadConfig builds a Config from DefaultConfig, the config file and
+// MIGRATOR_* environment variables, each overriding the one before. path
+// names the config file; when it is empty, the first of migrator.yaml,
+// migrator.yml and migrator.toml in the current directory is used, if any.
+// env selects a profile from the file's `environments` section, which
+// overrides the top-level settings:
+//
+//	user: app
+//	dbname: app
+//	environments:
+//	  staging:
+//	    host: staging-db.internal
+func LoadConfig(path, env string) (Config, error) {
+	config := DefaultConfig()
+
+	if path == "" {
+		for _, name := range configFiles {
+			if _, err := os.Stat(name); err == nil {
+				path = name
+				break
+			}
+		}
+	}
+	if path != "" {
+		if err := loadConfigFile(path, env, &config); err != nil {
+			return Config{}, err
+		}
+	} else if env != "" {
+		return Config{}, fmt.Errorf("environment %q requires a config file", env)
+	}
+
+	if err := applyEnv(&config, os.LookupEnv); err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+func loadConfigFile(path, env string, config *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = decodeYAMLConfig(data, env, config)
+	case ".toml":
+		err = decodeTOMLConfig(data, env, config)
+	default:
+		err = errors.New("unsupported format, expected .yaml, .yml or .toml")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load config file %s: %v", path, err)
+	}
+	return nil
+}
+
+// Decoding a profile into the already decoded config only overrides the
+// keys the profile sets.
+
+func decodeYAMLConfig(data []byte, env string, config *Config) error {
+	var file struct {
+		Config       `yaml:",inline"`
+		Environments map[string]yaml.Node `yaml:"environments"`
+	}
+	file.Config = *config
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	for name, profile := range file.Environments {
+		// Every profile is decoded, so that a typo in one that is not
+		// selected is reported as well, like it is for TOML.
+		target := Config{}
+		if name == env {
+			target = file.Config
+		}
+		if err := decodeYAMLNode(&profile, &target); err != nil {
+			return fmt.Errorf("environment %q: %v", name, err)
+		}
+		if name == env {
+			file.Config = target
+		}
+	}
+	if _, ok := file.Environments[env]; env != "" && !ok {
+		return fmt.Errorf("environment %q is not defined", env)
+	}
+	*config = file.Config
+	return nil
+}
+
+// decodeYAMLNode decodes node into v, rejecting unknown fields. Node.Decode
+// has no such option, so the node is encoded again and decoded strictly.
+func decodeYAMLNode(node *yaml.Node, v interface{}) error {
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+func decodeTOMLConfig(data []byte, env string, config *Config) error {
+	var file struct {
+		Config
+		Environments map[string]toml.Primitive `toml:"environments"`
+	}
+	file.Config = *config
+
+	md, err := toml.Decode(string(data), &file)
+	if err != nil {
+		return err
+	}
+	for name, profile := range file.Environments {
+		// Every profile is decoded, so that Undecoded only reports keys
+		// that are unknown rather than profiles that were not selected.
+		target := Config{}
+		if name == env {
+			target = file.Config
+		}
+		if err := md.PrimitiveDecode(profile, &target); err != nil {
+			return fmt.Errorf("environment %q: %v", name, err)
+		}
+		if name == env {
+			file.Config = target
+		}
+	}
+	if _, ok := file.Environments[env]; env != "" && !ok {
+		return fmt.Errorf("environment %q is not defined", env)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return fmt.Errorf("unknown setting %s", undecoded[0])
+	}
+	*config = file.Config
+	return nil
+}
+
+// applyEnv overrides config with the MIGRATOR_* variables that are set.
+func applyEnv(config *Config, lookup func(string) (string, bool)) error {
+	strs := map[string]*string{
+		"DATABASE_URL": &config.DatabaseURL,
+		"HOST":         &config.DBHost,
+		"USER":         &config.DBUser,
+		"PASSWORD":     &config.DBPassword,
+		"DBNAME":       &config.DBName,
+		"SSLMODE":      &config.SSLMode,
+		"SSLROOTCERT":  &config.SSLRootCert,
+		"SSLCERT":      &config.SSLCert,
+		"SSLKEY":       &config.SSLKey,
+		"OUTPUT":       &config.OutputDir,
+	}
+	for name, field := range strs {
+		if value, ok := lookup(envPrefix + name); ok {
+			*field = value
+		}
+	}
+
+	durations := map[string]*time.Duration{
+		"CONNECT_TIMEOUT":   &config.ConnectTimeout,
+		"STATEMENT_TIMEOUT": &config.StatementTimeout,
+	}
+	for name, field := range durations {
+		if value, ok := lookup(envPrefix + name); ok {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid %s%s %q", envPrefix, name, value)
+			}
+			*field = d
+		}
+	}
+
+	if value, ok := lookup(envPrefix + "PORT"); ok {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid %sPORT %q", envPrefix, value)
+		}
+		config.DBPort = port
+	}
+	if value, ok := lookup(envPrefix + "DEBUG"); ok {
+		debug, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid %sDEBUG %q", envPrefix, value)
+		}
+		config.Debug = debug
+	}
+	return nil
+}
+
+// Validate reports settings a Migrator cannot work without.
+func (c Config) Validate() error {
+	if c.DatabaseURL == "" && (c.DBUser == "" || c.DBName == "") {
+		return errors.New("a database URL, or a database user and name, are required")
+	}
+	return nil
+}
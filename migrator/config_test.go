@@ -0,0 +1,248 @@
+// File: migrator/config_test.go
+
+package migrator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const yamlConfig = `
+user: app
+dbname: app
+environments:
+  staging:
+    host: staging-db.internal
+    port: 6543
+  production:
+    host: db.internal
+`
+
+func TestDecodeYAMLConfigProfile(t *testing.T) {
+	config := DefaultConfig()
+	if err := decodeYAMLConfig([]byte(yamlConfig), "staging", &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.DBHost != "staging-db.internal" || config.DBPort != 6543 || config.DBUser != "app" || config.DBName != "app" {
+		t.Errorf("staging config = %+v", config)
+	}
+}
+
+func TestDecodeYAMLConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		env  string
+		want string
+	}{
+		{
+			name: "unknown top-level key",
+			data: "user: app\nhots: db\n",
+			want: "field hots not found",
+		},
+		{
+			name: "unknown key in selected profile",
+			data: "environments:\n  staging:\n    hots: db\n",
+			env:  "staging",
+			want: `environment "staging": yaml: unmarshal errors:`,
+		},
+		{
+			name: "unknown key in another profile",
+			data: "environments:\n  staging:\n    host: db\n  production:\n    pasword: secret\n",
+			env:  "staging",
+			want: "field pasword not found",
+		},
+		{
+			name: "undefined profile",
+			data: "environments:\n  staging:\n    host: db\n",
+			env:  "prod",
+			want: `environment "prod" is not defined`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			err := decodeYAMLConfig([]byte(tt.data), tt.env, &config)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("decodeYAMLConfig() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+const tomlConfig = `
+user = "app"
+dbname = "app"
+
+[environments.staging]
+host = "staging-db.internal"
+port = 6543
+
+[environments.production]
+host = "db.internal"
+`
+
+func TestDecodeTOMLConfigProfile(t *testing.T) {
+	config := DefaultConfig()
+	if err := decodeTOMLConfig([]byte(tomlConfig), "staging", &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.DBHost != "staging-db.internal" || config.DBPort != 6543 || config.DBUser != "app" || config.DBName != "app" {
+		t.Errorf("staging config = %+v", config)
+	}
+}
+
+func TestDecodeTOMLConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		env  string
+		want string
+	}{
+		{
+			name: "unknown top-level key",
+			data: "user = \"app\"\nhots = \"db\"\n",
+			want: "unknown setting hots",
+		},
+		{
+			name: "unknown key in selected profile",
+			data: "[environments.staging]\nhots = \"db\"\n",
+			env:  "staging",
+			want: "unknown setting environments.staging.hots",
+		},
+		{
+			name: "unknown key in another profile",
+			data: "[environments.staging]\nhost = \"db\"\n[environments.production]\npasword = \"secret\"\n",
+			env:  "staging",
+			want: "unknown setting environments.production.pasword",
+		},
+		{
+			name: "undefined profile",
+			data: "[environments.staging]\nhost = \"db\"\n",
+			env:  "prod",
+			want: `environment "prod" is not defined`,
+		},
+		{
+			name: "wrong type in profile",
+			data: "[environments.staging]\nport = \"many\"\n",
+			env:  "staging",
+			want: `environment "staging":`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			err := decodeTOMLConfig([]byte(tt.data), tt.env, &config)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("decodeTOMLConfig() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    func(*Config)
+		wantErr string
+	}{
+		{
+			name: "nothing set",
+			want: func(*Config) {},
+		},
+		{
+			name: "strings",
+			env: map[string]string{
+				"MIGRATOR_DATABASE_URL": "postgres://db/app",
+				"MIGRATOR_HOST":         "db.internal",
+				"MIGRATOR_USER":         "app",
+				"MIGRATOR_PASSWORD":     "secret",
+				"MIGRATOR_DBNAME":       "shop",
+				"MIGRATOR_SSLMODE":      "verify-full",
+				"MIGRATOR_SSLROOTCERT":  "ca.pem",
+				"MIGRATOR_SSLCERT":      "client.pem",
+				"MIGRATOR_SSLKEY":       "client.key",
+				"MIGRATOR_OUTPUT":       "db/migrations",
+			},
+			want: func(c *Config) {
+				c.DatabaseURL = "postgres://db/app"
+				c.DBHost = "db.internal"
+				c.DBUser = "app"
+				c.DBPassword = "secret"
+				c.DBName = "shop"
+				c.SSLMode = "verify-full"
+				c.SSLRootCert = "ca.pem"
+				c.SSLCert = "client.pem"
+				c.SSLKey = "client.key"
+				c.OutputDir = "db/migrations"
+			},
+		},
+		{
+			name: "set but empty",
+			env:  map[string]string{"MIGRATOR_HOST": ""},
+			want: func(c *Config) { c.DBHost = "" },
+		},
+		{
+			name: "parsed values",
+			env: map[string]string{
+				"MIGRATOR_PORT":              "6543",
+				"MIGRATOR_DEBUG":             "true",
+				"MIGRATOR_CONNECT_TIMEOUT":   "5s",
+				"MIGRATOR_STATEMENT_TIMEOUT": "1m30s",
+			},
+			want: func(c *Config) {
+				c.DBPort = 6543
+				c.Debug = true
+				c.ConnectTimeout = 5 * time.Second
+				c.StatementTimeout = 90 * time.Second
+			},
+		},
+		{
+			name:    "invalid port",
+			env:     map[string]string{"MIGRATOR_PORT": "five"},
+			wantErr: `invalid MIGRATOR_PORT "five"`,
+		},
+		{
+			name:    "invalid debug",
+			env:     map[string]string{"MIGRATOR_DEBUG": "sometimes"},
+			wantErr: `invalid MIGRATOR_DEBUG "sometimes"`,
+		},
+		{
+			name:    "invalid connect timeout",
+			env:     map[string]string{"MIGRATOR_CONNECT_TIMEOUT": "5"},
+			wantErr: `invalid MIGRATOR_CONNECT_TIMEOUT "5"`,
+		},
+		{
+			name:    "invalid statement timeout",
+			env:     map[string]string{"MIGRATOR_STATEMENT_TIMEOUT": "soon"},
+			wantErr: `invalid MIGRATOR_STATEMENT_TIMEOUT "soon"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookup := func(key string) (string, bool) {
+				value, ok := tt.env[key]
+				return value, ok
+			}
+			config := DefaultConfig()
+			err := applyEnv(&config, lookup)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("applyEnv() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := DefaultConfig()
+			tt.want(&want)
+			if !reflect.DeepEqual(config, want) {
+				t.Errorf("applyEnv() = %+v, want %+v", config, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// File: migrator/constraints.go
+
+package migrator
+
+import (
+	"sort"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+// modelConstraints returns the unique constraints of the fields tagged
+// `unique` and the check constraints declared with `check`, as parsed and
+// named by GORM's own parsers. Both are ordered by name.
+func (m *Migrator) modelConstraints(info *modelInfo) []Constraint {
+	columns := make(map[*schema.Field]bool)
+	for _, field := range info.columnFields() {
+		columns[field] = true
+	}
+
+	uniques := info.Schema.ParseUniqueConstraints()
+	names := make([]string, 0, len(uniques))
+	for name, unique := range uniques {
+		if columns[unique.Field] && !unique.Field.PrimaryKey {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	constraints := make([]Constraint, 0, len(names))
+	for _, name := range names {
+		constraints = append(constraints, Constraint{Name: name, Columns: []string{uniques[name].Field.DBName}})
+	}
+
+	checks := info.Schema.ParseCheckConstraints()
+	names = names[:0]
+	for name, check := range checks {
+		if columns[check.Field] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		constraints = append(constraints, Constraint{Name: name, Definition: checks[name].Constraint})
+	}
+	return constraints
+}
+
+// comparePrimaryKey adds the model's primary key when the table has none,
+// and replaces the live one, under its existing name, when it covers other
+// columns. A model without a primary key leaves the table's alone.
+func comparePrimaryKey(info *modelInfo, table *Table) []SchemaChange {
+	columns := info.Schema.PrimaryFieldDBNames
+	if len(columns) == 0 {
+		return nil
+	}
+
+	live := table.PrimaryKey
+	if live == nil {
+		// Postgres names an unnamed primary key <table>_pkey.
+		primaryKey := Constraint{Name: table.Name + "_pkey", Columns: columns, Primary: true}
+		return []SchemaChange{AddConstraint{Table: table.Name, Constraint: primaryKey}}
+	}
+	if strings.Join(live.Columns, ",") == strings.Join(columns, ",") {
+		return nil
+	}
+	primaryKey := Constraint{Name: live.Name, Columns: columns, Primary: true}
+	return []SchemaChange{
+		DropConstraint{Table: table.Name, Constraint: *live},
+		AddConstraint{Table: table.Name, Constraint: primaryKey},
+	}
+}
+
+// compareConstraints diffs the model's primary key and constraints against
+// the live table. A unique constraint on the same column under another name counts as
+// present; check constraints are matched by name and recreated when their
+// expression changed. Constraints the model no longer declares are only
+// dropped when they carry the name GORM gives a column's unique or check
+// constraint, since others may have been created outside of the models.
+func (m *Migrator) compareConstraints(info *modelInfo, table *Table) []SchemaChange {
+	changes := comparePrimaryKey(info, table)
+	declared := make(map[string]bool)
+	for _, constraint := range m.modelConstraints(info) {
+		declared[constraint.Name] = true
+		if len(constraint.Columns) > 0 {
+			if !table.hasUnique(constraint.Columns) {
+				changes = append(changes, AddConstraint{Table: table.Name, Constraint: constraint})
+			}
+			continue
+		}
+
+		live := table.Check(constraint.Name)
+		switch {
+		case live == nil:
+			changes = append(changes, AddConstraint{Table: table.Name, Constraint: constraint})
+		case normalizeExpression(live.Definition) != normalizeExpression(constraint.Definition):
+			changes = append(changes,
+				DropConstraint{Table: table.Name, Constraint: *live},
+				AddConstraint{Table: table.Name, Constraint: constraint})
+		}
+	}
+
+	for _, field := range info.columnFields() {
+		name := m.db.NamingStrategy.UniqueName(info.Table, field.DBName)
+		for _, live := range table.Uniques {
+			if live.Name == name && !declared[name] {
+				changes = append(changes, DropConstraint{Table: table.Name, Constraint: live})
+			}
+		}
+		name = m.db.NamingStrategy.CheckerName(info.Table, field.DBName)
+		if live := table.Check(name); live != nil && !declared[name] {
+			changes = append(changes, DropConstraint{Table: table.Name, Constraint: *live})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,117 @@
+// File: migrator/constraints_test.go
+
+package migrator
+
+import "testing"
+
+type constraintModel struct {
+	ID    uint
+	Email string `gorm:"unique"`
+	Code  string `gorm:"unique"`
+	Age   int    `gorm:"check:age >= 0"`
+	Score int    `gorm:"check:score_range,score BETWEEN 0 AND 100;comment:out of 100"`
+	Nick  string
+	Note  string
+}
+
+func TestCompareConstraints(t *testing.T) {
+	m := newTestMigrator(t)
+	info := parseTestModel(t, m, &constraintModel{})
+	live := &Table{
+		Name: "constraint_models",
+		Columns: []*Column{
+			{Name: "id", Type: "bigint", NotNull: true},
+			{Name: "email", Type: "text"},
+			{Name: "code", Type: "text"},
+			{Name: "age", Type: "bigint"},
+			{Name: "score", Type: "bigint", Comment: "percent"},
+			{Name: "nick", Type: "text"},
+			{Name: "note", Type: "text", Comment: "free text"},
+		},
+		PrimaryKey: &Constraint{Name: "constraint_models_pkey", Columns: []string{"id"}, Primary: true},
+		Uniques: []Constraint{
+			// Created by hand under another name, so it counts as present.
+			{Name: "constraint_models_email_key", Columns: []string{"email"}},
+			// Named by GORM for a column that is no longer unique.
+			{Name: "uni_constraint_models_nick", Columns: []string{"nick"}},
+		},
+		Checks: []Constraint{
+			// Deparsed by Postgres with extra parentheses, but unchanged.
+			{Name: "chk_constraint_models_age", Definition: "(age >= 0)"},
+			{Name: "score_range", Definition: "((score >= 0) AND (score <= 10))"},
+			{Name: "chk_constraint_models_note", Definition: "(length(note) > 0)"},
+		},
+	}
+
+	changes := m.compareModelToTable(info, live)
+	changes = append(changes, m.compareConstraints(info, live)...)
+
+	wantUp := `COMMENT ON COLUMN "constraint_models"."score" IS 'out of 100';
+COMMENT ON COLUMN "constraint_models"."note" IS NULL;
+ALTER TABLE "constraint_models" ADD CONSTRAINT "uni_constraint_models_code" UNIQUE ("code");
+ALTER TABLE "constraint_models" DROP CONSTRAINT IF EXISTS "score_range";
+ALTER TABLE "constraint_models" ADD CONSTRAINT "score_range" CHECK (score BETWEEN 0 AND 100);
+ALTER TABLE "constraint_models" DROP CONSTRAINT IF EXISTS "uni_constraint_models_nick";
+ALTER TABLE "constraint_models" DROP CONSTRAINT IF EXISTS "chk_constraint_models_note";`
+	if got := renderUp(m.dialect, changes); got != wantUp {
+		t.Errorf("up =\n%s\nwant\n%s", got, wantUp)
+	}
+
+	wantDown := `ALTER TABLE "constraint_models" ADD CONSTRAINT "chk_constraint_models_note" CHECK ((length(note) > 0));
+ALTER TABLE "constraint_models" ADD CONSTRAINT "uni_constraint_models_nick" UNIQUE ("nick");
+ALTER TABLE "constraint_models" DROP CONSTRAINT IF EXISTS "score_range";
+ALTER TABLE "constraint_models" ADD CONSTRAINT "score_range" CHECK (((score >= 0) AND (score <= 10)));
+ALTER TABLE "constraint_models" DROP CONSTRAINT IF EXISTS "uni_constraint_models_code";
+COMMENT ON COLUMN "constraint_models"."note" IS 'free text';
+COMMENT ON COLUMN "constraint_models"."score" IS 'percent';`
+	if got := renderDown(m.dialect, changes); got != wantDown {
+		t.Errorf("down =\n%s\nwant\n%s", got, wantDown)
+	}
+}
+
+type primaryKeyModel struct {
+	TenantID uint `gorm:"primaryKey;autoIncrement:false"`
+	ID       uint `gorm:"primaryKey"`
+	Name     string
+}
+
+func TestComparePrimaryKey(t *testing.T) {
+	m := newTestMigrator(t)
+	info := parseTestModel(t, m, &primaryKeyModel{})
+
+	tests := []struct {
+		name     string
+		live     *Constraint
+		up, down string
+	}{
+		{
+			name: "unchanged",
+			live: &Constraint{Name: "primary_key_models_pkey", Columns: []string{"tenant_id", "id"}, Primary: true},
+		},
+		{
+			name: "missing",
+			up:   `ALTER TABLE "primary_key_models" ADD CONSTRAINT "primary_key_models_pkey" PRIMARY KEY ("tenant_id", "id");`,
+			down: `ALTER TABLE "primary_key_models" DROP CONSTRAINT IF EXISTS "primary_key_models_pkey";`,
+		},
+		{
+			name: "other columns",
+			live: &Constraint{Name: "pk_primary_key_models", Columns: []string{"id"}, Primary: true},
+			up: `ALTER TABLE "primary_key_models" DROP CONSTRAINT IF EXISTS "pk_primary_key_models";
+ALTER TABLE "primary_key_models" ADD CONSTRAINT "pk_primary_key_models" PRIMARY KEY ("tenant_id", "id");`,
+			down: `ALTER TABLE "primary_key_models" DROP CONSTRAINT IF EXISTS "pk_primary_key_models";
+ALTER TABLE "primary_key_models" ADD CONSTRAINT "pk_primary_key_models" PRIMARY KEY ("id");`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			live := &Table{Name: "primary_key_models", PrimaryKey: tt.live}
+			changes := m.compareConstraints(info, live)
+			if got := renderUp(m.dialect, changes); got != tt.up {
+				t.Errorf("up =\n%s\nwant\n%s", got, tt.up)
+			}
+			if got := renderDown(m.dialect, changes); got != tt.down {
+				t.Errorf("down =\n%s\nwant\n%s", got, tt.down)
+			}
+		})
+	}
+}
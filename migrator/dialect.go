@@ -0,0 +1,38 @@
+// File: migrator/dialect.go
+
+package migrator
+
+import "gorm.io/gorm/schema"
+
+// Dialect maps model fields to column types and renders every DDL
+// statement the migrator generates. Creating a table, altering it and
+// diffing it against the live schema all go through the same Dialect, so a
+// type is spelled and compared the same way on every path.
+type Dialect interface {
+	// DataType returns the column type of field once registered
+	// TypeMappers and GormDBDataType have been consulted.
+	DataType(field *schema.Field) string
+	// NormalizeType splits a type into its canonical name and modifiers, so
+	// that equivalent spellings such as VARCHAR(20) and
+	// character varying(20) compare equal.
+	NormalizeType(typ string) (name, modifiers string)
+
+	CreateTable(table string, columns []Column, primaryKey []string, constraints []Constraint) string
+	DropTable(table string) string
+	ColumnComment(table, column, comment string) string
+
+	AddColumn(table string, column Column) string
+	DropColumn(table, column string) string
+	AlterColumnType(table, column, typ string) string
+	SetNotNull(table, column string, notNull bool) string
+	SetDefault(table, column, value string) string
+
+	AddConstraint(table string, constraint Constraint) string
+	DropConstraint(table, name string) string
+
+	CreateIndex(table string, index Index) string
+	DropIndex(table string, index Index) string
+
+	AddForeignKey(table string, fk ForeignKey) string
+	DropForeignKey(table string, fk ForeignKey) string
+}
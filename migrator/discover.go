@@ -0,0 +1,333 @@
+// File: migrator/discover.go
+
+package migrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"golang.org/x/tools/go/packages"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils"
+)
+
+// ModelType identifies a model type by import path and type name.
+type ModelType struct {
+	PkgPath string
+	Name    string
+}
+
+func (t ModelType) String() string {
+	return t.PkgPath + "." + t.Name
+}
+
+// Discovery holds the models found by DiscoverModels and the module that
+// contains them.
+type Discovery struct {
+	ModuleDir string
+	Models    []ModelType
+}
+
+// DiscoverModels loads the packages matching patterns, e.g.
+// "./internal/models/...", relative to dir and returns every exported
+// struct type that embeds gorm.Model or has a field with a `gorm` tag.
+// Structs that other models embed and that have no primary key, like a
+// set of audit columns or a type used with `gorm:"embedded"`, are columns
+// of those models rather than tables, so they are left out. An embedded
+// struct with a primary key, like User in
+//
+//	type Admin struct {
+//		User
+//		Level int
+//	}
+//
+// can be a table of its own and is kept.
+func DiscoverModels(dir string, patterns ...string) (*Discovery, error) {
+	// Dependencies are type-checked from source rather than read from
+	// export data, whose format depends on the Go toolchain in use.
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedModule |
+			packages.NeedImports | packages.NeedDeps | packages.NeedSyntax,
+		Dir: dir,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %v", err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, fmt.Errorf("failed to load packages %v", patterns)
+	}
+
+	d := &Discovery{}
+	var candidates []*types.TypeName
+	embedded := make(map[*types.TypeName]bool)
+	for _, pkg := range pkgs {
+		if pkg.Module == nil {
+			return nil, fmt.Errorf("package %s is not part of a module", pkg.PkgPath)
+		}
+		if d.ModuleDir == "" {
+			d.ModuleDir = pkg.Module.Dir
+		} else if d.ModuleDir != pkg.Module.Dir {
+			return nil, fmt.Errorf("packages %v span more than one module", patterns)
+		}
+
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || !obj.Exported() || obj.IsAlias() {
+				continue
+			}
+			named, ok := obj.Type().(*types.Named)
+			if !ok || named.TypeParams().Len() > 0 {
+				continue
+			}
+			st, ok := named.Underlying().(*types.Struct)
+			if !ok || !isModelStruct(st, make(map[*types.Struct]bool)) {
+				continue
+			}
+			candidates = append(candidates, obj)
+			for _, t := range embeddedTypes(st) {
+				embedded[t] = true
+			}
+		}
+	}
+
+	for _, obj := range candidates {
+		st := obj.Type().Underlying().(*types.Struct)
+		if !embedded[obj] || hasPrimaryKey(st, make(map[*types.Struct]bool)) {
+			d.Models = append(d.Models, ModelType{PkgPath: obj.Pkg().Path(), Name: obj.Name()})
+		}
+	}
+	sort.Slice(d.Models, func(i, j int) bool { return d.Models[i].String() < d.Models[j].String() })
+	return d, nil
+}
+
+// isModelStruct reports whether st embeds gorm.Model or has a field with a
+// `gorm` tag, directly or through an embedded struct.
+func isModelStruct(st *types.Struct, seen map[*types.Struct]bool) bool {
+	if seen[st] {
+		return false
+	}
+	seen[st] = true
+
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if _, ok := reflect.StructTag(st.Tag(i)).Lookup("gorm"); ok {
+			return true
+		}
+		if !field.Embedded() {
+			continue
+		}
+		named := namedType(field.Type())
+		if named == nil {
+			continue
+		}
+		if obj := named.Obj(); obj.Pkg() != nil && obj.Pkg().Path() == "gorm.io/gorm" && obj.Name() == "Model" {
+			return true
+		}
+		if inner, ok := named.Underlying().(*types.Struct); ok && isModelStruct(inner, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPrimaryKey reports whether st has a field GORM uses as primary key:
+// one tagged `primaryKey`, or else one named ID, directly or through an
+// embedded struct such as gorm.Model.
+func hasPrimaryKey(st *types.Struct, seen map[*types.Struct]bool) bool {
+	if seen[st] {
+		return false
+	}
+	seen[st] = true
+
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		settings := gormSettings(st, i)
+		if ignoredForMigration(settings) {
+			continue
+		}
+		if utils.CheckTruth(settings["PRIMARYKEY"], settings["PRIMARY_KEY"]) || field.Name() == "ID" && !field.Embedded() {
+			return true
+		}
+		if _, ok := settings["EMBEDDED"]; !ok && !field.Embedded() {
+			continue
+		}
+		if named := namedType(field.Type()); named != nil {
+			if inner, ok := named.Underlying().(*types.Struct); ok && hasPrimaryKey(inner, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// embeddedTypes returns the struct types st embeds, either as anonymous
+// fields or with a `gorm:"embedded"` tag.
+func embeddedTypes(st *types.Struct) []*types.TypeName {
+	var names []*types.TypeName
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if _, ok := gormSettings(st, i)["EMBEDDED"]; !ok && !field.Embedded() {
+			continue
+		}
+		if named := namedType(field.Type()); named != nil {
+			names = append(names, named.Obj())
+		}
+	}
+	return names
+}
+
+// gormSettings parses the `gorm` tag of the i-th field of st the way GORM
+// parses the tags of the fields it sees.
+func gormSettings(st *types.Struct, i int) map[string]string {
+	return schema.ParseTagSetting(reflect.StructTag(st.Tag(i)).Get("gorm"), ";")
+}
+
+// ignoredForMigration reports whether the settings exclude the field from
+// migrations, which `-`, `-:all` and `-:migration` do.
+func ignoredForMigration(settings map[string]string) bool {
+	value, ok := settings["-"]
+	if !ok {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "-", "all", "migration":
+		return true
+	}
+	return false
+}
+
+func namedType(t types.Type) *types.Named {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, _ := t.(*types.Named)
+	return named
+}
+
+var registrationProgram = template.Must(template.New("main").Parse(`// Code generated by go-migrator. DO NOT EDIT.
+
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/hashirventhodi/go-migrator/migrator"
+{{range $i, $pkg := .Packages}}
+	m{{$i}} "{{$pkg}}"{{end}}
+)
+
+func main() {
+	migrator.RegisterModels({{range .Models}}
+		&m{{.Index}}.{{.Name}}{},{{end}}
+	)
+
+	var config migrator.Config
+	if err := json.NewDecoder(os.Stdin).Decode(&config); err != nil {
+		log.Fatalf("Failed to read config: %v", err)
+	}
+
+	m, err := migrator.New(config)
+	if err != nil {
+		log.Fatalf("Failed to create migrator: %v", err)
+	}
+	if err := m.GenerateMigrations(); err != nil {
+		log.Fatalf("Failed to generate migrations: %v", err)
+	}
+}
+`))
+
+// registrationSource renders a program that registers the discovered
+// models and generates migrations with the config file named by its first
+// argument.
+func (d *Discovery) registrationSource() ([]byte, error) {
+	type model struct {
+		Index int
+		Name  string
+	}
+	var data struct {
+		Packages []string
+		Models   []model
+	}
+	index := make(map[string]int)
+	for _, t := range d.Models {
+		i, ok := index[t.PkgPath]
+		if !ok {
+			i = len(data.Packages)
+			index[t.PkgPath] = i
+			data.Packages = append(data.Packages, t.PkgPath)
+		}
+		data.Models = append(data.Models, model{Index: i, Name: t.Name})
+	}
+
+	var buf bytes.Buffer
+	if err := registrationProgram.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// Generate compiles and runs a program inside the models' module that
+// registers the discovered models and generates migrations with config.
+// The models can only be imported from code built in their own module,
+// which therefore has to require github.com/hashirventhodi/go-migrator.
+// The config, which may hold credentials, is passed on standard input so
+// that only the generated source is ever written to disk.
+func (d *Discovery) Generate(ctx context.Context, config Config) error {
+	if len(d.Models) == 0 {
+		return fmt.Errorf("no models found")
+	}
+
+	// The program runs in the module directory, so relative output paths
+	// are resolved against the current directory first.
+	outputDir, err := filepath.Abs(config.OutputDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve output directory: %v", err)
+	}
+	config.OutputDir = outputDir
+
+	source, err := d.registrationSource()
+	if err != nil {
+		return fmt.Errorf("failed to generate registration program: %v", err)
+	}
+	configData, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %v", err)
+	}
+
+	// The directory name starts with a dot so that ./... patterns in the
+	// module never pick it up.
+	dir, err := os.MkdirTemp(d.ModuleDir, ".go-migrator-")
+	if err != nil {
+		return fmt.Errorf("failed to create registration program: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), source, 0o644); err != nil {
+		return fmt.Errorf("failed to write registration program: %v", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "run", "./"+filepath.Base(dir))
+	cmd.Dir = d.ModuleDir
+	cmd.Stdin = bytes.NewReader(configData)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("registration program failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// File: migrator/discover_test.go
+
+package migrator
+
+import (
+	"go/types"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const discoverTestPackage = "github.com/hashirventhodi/go-migrator/migrator/testdata/discover"
+
+func TestDiscoverModels(t *testing.T) {
+	d, err := DiscoverModels(".", "./testdata/discover")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, model := range d.Models {
+		if model.PkgPath != discoverTestPackage {
+			t.Errorf("model %s is not from %s", model, discoverTestPackage)
+		}
+		got = append(got, model.Name)
+	}
+	want := []string{"Admin", "Keyed", "Note", "Shop", "Tenant", "User"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiscoverModels() = %v, want %v", got, want)
+	}
+}
+
+func TestHasPrimaryKey(t *testing.T) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedTypes | packages.NeedDeps | packages.NeedImports}, "./testdata/discover")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		t.Fatal("failed to load the test package")
+	}
+	scope := pkgs[0].Types.Scope()
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Base", false},
+		{"User", true},
+		{"Admin", true},
+		{"Address", false},
+		{"Shop", true},
+		{"Keyed", true},
+		{"Tenant", true},
+		{"Hidden", false},
+		{"Note", true},
+		{"Plain", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := scope.Lookup(tt.name).Type().Underlying().(*types.Struct)
+			if got := hasPrimaryKey(st, make(map[*types.Struct]bool)); got != tt.want {
+				t.Errorf("hasPrimaryKey(%s) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
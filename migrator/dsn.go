@@ -0,0 +1,116 @@
+// File: migrator/dsn.go
+
+package migrator
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DSN returns the connection string for the config. When DatabaseURL is
+// set it is used as the base, as either a postgres:// URL or a key/value
+// DSN, and DBHost, DBPort and DBName are ignored. A user or password it
+// leaves out is taken from DBUser and DBPassword, so that the password can
+// come from MIGRATOR_PASSWORD. The TLS and timeout settings are added on
+// top of either form.
+func (c Config) DSN() (string, error) {
+	options := c.connectionOptions()
+
+	if c.DatabaseURL == "" {
+		pairs := [][2]string{
+			{"host", c.DBHost},
+			{"port", strconv.Itoa(c.DBPort)},
+			{"user", c.DBUser},
+			{"password", c.DBPassword},
+			{"dbname", c.DBName},
+		}
+		if c.SSLMode == "" {
+			pairs = append(pairs, [2]string{"sslmode", "disable"})
+		}
+		return keyValueDSN(append(pairs, options...)), nil
+	}
+
+	if strings.HasPrefix(c.DatabaseURL, "postgres://") || strings.HasPrefix(c.DatabaseURL, "postgresql://") {
+		u, err := url.Parse(c.DatabaseURL)
+		if err != nil {
+			// The error would include the URL and with it the password.
+			return "", fmt.Errorf("invalid database URL")
+		}
+		username := u.User.Username()
+		if username == "" {
+			username = c.DBUser
+		}
+		password, hasPassword := u.User.Password()
+		if !hasPassword && c.DBPassword != "" {
+			password, hasPassword = c.DBPassword, true
+		}
+		switch {
+		case hasPassword:
+			u.User = url.UserPassword(username, password)
+		case username != "":
+			u.User = url.User(username)
+		}
+
+		query := u.Query()
+		for _, option := range options {
+			query.Set(option[0], option[1])
+		}
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+	}
+
+	// Later keys override earlier ones in a key/value DSN, so the user and
+	// password go first and the options last.
+	dsn := strings.TrimSpace(c.DatabaseURL)
+	var credentials [][2]string
+	if c.DBUser != "" {
+		credentials = append(credentials, [2]string{"user", c.DBUser})
+	}
+	if c.DBPassword != "" {
+		credentials = append(credentials, [2]string{"password", c.DBPassword})
+	}
+	if len(credentials) > 0 {
+		dsn = keyValueDSN(credentials) + " " + dsn
+	}
+	if len(options) > 0 {
+		dsn += " " + keyValueDSN(options)
+	}
+	return dsn, nil
+}
+
+// connectionOptions returns the TLS and timeout settings that are set.
+func (c Config) connectionOptions() [][2]string {
+	var options [][2]string
+	add := func(key, value string) {
+		if value != "" {
+			options = append(options, [2]string{key, value})
+		}
+	}
+	add("sslmode", c.SSLMode)
+	add("sslrootcert", c.SSLRootCert)
+	add("sslcert", c.SSLCert)
+	add("sslkey", c.SSLKey)
+	if c.ConnectTimeout > 0 {
+		// connect_timeout is in whole seconds, so round up.
+		add("connect_timeout", strconv.FormatInt(int64((c.ConnectTimeout+time.Second-1)/time.Second), 10))
+	}
+	if c.StatementTimeout > 0 {
+		add("statement_timeout", strconv.FormatInt(c.StatementTimeout.Milliseconds(), 10))
+	}
+	return options
+}
+
+// keyValueDSN renders key='value' pairs, quoting every value so that
+// spaces, quotes and backslashes in passwords or paths survive.
+func keyValueDSN(pairs [][2]string) string {
+	parts := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		value := strings.ReplaceAll(pair[1], `\`, `\\`)
+		value = strings.ReplaceAll(value, `'`, `\'`)
+		parts = append(parts, fmt.Sprintf("%s='%s'", pair[0], value))
+	}
+	return strings.Join(parts, " ")
+}
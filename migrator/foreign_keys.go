@@ -0,0 +1,119 @@
+// File: migrator/foreign_keys.go
+
+package migrator
+
+import (
+	"log"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+// modelForeignKeys returns the foreign-key constraints GORM derives from
+// the belongs-to, has-one and has-many relationships of the given models.
+// A has-one or has-many constraint lives on the other model's table.
+func (m *Migrator) modelForeignKeys(infos []*modelInfo) []AddForeignKey {
+	seen := make(map[string]bool)
+	var foreignKeys []AddForeignKey
+
+	for _, info := range infos {
+		names := make([]string, 0, len(info.Schema.Relationships.Relations))
+		for name := range info.Schema.Relationships.Relations {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			rel := info.Schema.Relationships.Relations[name]
+			if rel.Field.IgnoreMigration || rel.JoinTable != nil {
+				continue
+			}
+			if rel.Polymorphic != nil {
+				if m.config.Debug {
+					log.Printf("Skipping foreign key for polymorphic relationship %s.%s", info.Schema.Name, rel.Name)
+				}
+				continue
+			}
+
+			constraint := rel.ParseConstraint()
+			if constraint == nil || constraint.Schema == nil || constraint.ReferenceSchema == nil {
+				continue
+			}
+
+			fk := AddForeignKey{
+				Table: constraint.Schema.Table,
+				ForeignKey: ForeignKey{
+					Name:       constraint.Name,
+					Columns:    fieldDBNames(constraint.ForeignKeys),
+					RefTable:   constraint.ReferenceSchema.Table,
+					RefColumns: fieldDBNames(constraint.References),
+					OnUpdate:   strings.ToUpper(strings.TrimSpace(constraint.OnUpdate)),
+					OnDelete:   strings.ToUpper(strings.TrimSpace(constraint.OnDelete)),
+				},
+			}
+			key := fk.Table + "." + fk.ForeignKey.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			foreignKeys = append(foreignKeys, fk)
+		}
+	}
+	return foreignKeys
+}
+
+// compareForeignKeys returns the changes needed to create every model
+// foreign key that is missing or different in the live schema. tables holds
+// the tables created in this batch. Constraints that exist only in the
+// database are left alone.
+func (m *Migrator) compareForeignKeys(infos []*modelInfo, live *Schema, tables map[string]bool) []SchemaChange {
+	var changes []SchemaChange
+	for _, fk := range m.modelForeignKeys(infos) {
+		if !tables[fk.Table] && live.Table(fk.Table) == nil {
+			log.Printf("Skipping foreign key %s: table %s is not a registered model", fk.ForeignKey.Name, fk.Table)
+			continue
+		}
+		if !tables[fk.ForeignKey.RefTable] && live.Table(fk.ForeignKey.RefTable) == nil {
+			log.Printf("Skipping foreign key %s: referenced table %s does not exist", fk.ForeignKey.Name, fk.ForeignKey.RefTable)
+			continue
+		}
+
+		var existing *ForeignKey
+		if table := live.Table(fk.Table); table != nil {
+			existing = table.ForeignKey(fk.ForeignKey.Name)
+		}
+		switch {
+		case existing == nil:
+			changes = append(changes, fk)
+		case !sameForeignKey(fk.ForeignKey, *existing):
+			changes = append(changes, DropForeignKey{Table: fk.Table, ForeignKey: *existing}, fk)
+		}
+	}
+	return changes
+}
+
+func sameForeignKey(a, b ForeignKey) bool {
+	return a.RefTable == b.RefTable &&
+		strings.Join(a.Columns, ",") == strings.Join(b.Columns, ",") &&
+		strings.Join(a.RefColumns, ",") == strings.Join(b.RefColumns, ",") &&
+		foreignKeyAction(a.OnUpdate) == foreignKeyAction(b.OnUpdate) &&
+		foreignKeyAction(a.OnDelete) == foreignKeyAction(b.OnDelete)
+}
+
+// foreignKeyAction treats an empty action as the default, NO ACTION.
+func foreignKeyAction(action string) string {
+	action = strings.ToUpper(strings.TrimSpace(action))
+	if action == "NO ACTION" {
+		return ""
+	}
+	return action
+}
+
+func fieldDBNames(fields []*schema.Field) []string {
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field.DBName)
+	}
+	return names
+}
@@ -0,0 +1,219 @@
+// File: migrator/indexes.go
+
+package migrator
+
+import (
+	"log"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// modelIndexes returns the indexes declared with `index` and `uniqueIndex`
+// tags, named and ordered the way GORM's own parser resolves them.
+func (m *Migrator) modelIndexes(info *modelInfo) []Index {
+	parsed := info.Schema.ParseIndexes()
+
+	names := make([]string, 0, len(parsed))
+	for name := range parsed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	indexes := make([]Index, 0, len(names))
+	for _, name := range names {
+		gi := parsed[name]
+		index := Index{
+			Name:   gi.Name,
+			Unique: strings.EqualFold(gi.Class, "UNIQUE"),
+			Method: strings.ToLower(gi.Type),
+			Where:  gi.Where,
+			Option: gi.Option,
+		}
+		for _, opt := range gi.Fields {
+			column := opt.Expression
+			if column == "" {
+				column = opt.DBName
+			}
+			if opt.Collate != "" {
+				column += " COLLATE " + opt.Collate
+			}
+			if opt.Sort != "" {
+				column += " " + strings.ToUpper(opt.Sort)
+			}
+			index.Columns = append(index.Columns, column)
+		}
+		indexes = append(indexes, index)
+	}
+
+	return append(indexes, m.softDeleteIndexes(info, indexes)...)
+}
+
+// softDeleteIndexes returns an index for every gorm.DeletedAt column that
+// is not already the leading column of a declared index. GORM filters on
+// that column in every query, and gorm.Model declares the index itself.
+func (m *Migrator) softDeleteIndexes(info *modelInfo, declared []Index) []Index {
+	leading := make(map[string]bool)
+	for _, index := range declared {
+		if len(index.Columns) > 0 {
+			leading[indexKeyExpression(index.Columns[0])] = true
+		}
+	}
+
+	var indexes []Index
+	for _, field := range info.columnFields() {
+		if field.IndirectFieldType != reflect.TypeOf(gorm.DeletedAt{}) || leading[field.DBName] {
+			continue
+		}
+		indexes = append(indexes, Index{
+			Name:    m.db.NamingStrategy.IndexName(info.Table, field.DBName),
+			Columns: []string{field.DBName},
+		})
+	}
+	return indexes
+}
+
+func (m *Migrator) createIndexChanges(info *modelInfo) []SchemaChange {
+	var changes []SchemaChange
+	for _, index := range m.modelIndexes(info) {
+		changes = append(changes, AddIndex{Table: info.Table, Index: index})
+	}
+	return changes
+}
+
+// compareIndexes diffs the model's indexes against the live table. Indexes
+// that exist only in the database are left alone, since they may have been
+// created outside of the models.
+func (m *Migrator) compareIndexes(info *modelInfo, table *Table) []SchemaChange {
+	var changes []SchemaChange
+	for _, index := range m.modelIndexes(info) {
+		live := table.Index(index.Name)
+		if live == nil {
+			changes = append(changes, AddIndex{Table: table.Name, Index: index})
+			continue
+		}
+		if sameIndex(index, *live) {
+			continue
+		}
+		if live.Primary || live.Constraint {
+			log.Printf("Index %s on %s backs a constraint and differs from the model; not recreating it", live.Name, table.Name)
+			continue
+		}
+		changes = append(changes,
+			DropIndex{Table: table.Name, Index: *live},
+			AddIndex{Table: table.Name, Index: index})
+	}
+
+	if m.config.Debug {
+		declared := make(map[string]bool)
+		for _, index := range m.modelIndexes(info) {
+			declared[index.Name] = true
+		}
+		for _, live := range table.Indexes {
+			if !declared[live.Name] && !live.Primary && !live.Constraint {
+				log.Printf("Index %s on %s is not declared by the model", live.Name, table.Name)
+			}
+		}
+	}
+	return changes
+}
+
+// sameIndex compares a model index with an introspected one. Postgres
+// reports key columns without COLLATE or sort order, so those are
+// ignored, and quotes reserved-word and mixed-case column names.
+func sameIndex(model, live Index) bool {
+	if model.Unique != live.Unique {
+		return false
+	}
+	if indexMethod(model.Method) != indexMethod(live.Method) {
+		return false
+	}
+	if normalizeExpression(model.Where) != normalizeExpression(live.Where) {
+		return false
+	}
+	if len(model.Columns) != len(live.Columns) {
+		return false
+	}
+	for i := range model.Columns {
+		if normalizeExpression(indexKeyExpression(model.Columns[i])) != normalizeExpression(unquoteIdent(live.Columns[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// isConcurrentIndex reports whether index is declared with
+// `option:CONCURRENTLY`.
+func isConcurrentIndex(index Index) bool {
+	return strings.EqualFold(strings.TrimSpace(index.Option), "CONCURRENTLY")
+}
+
+func indexMethod(method string) string {
+	if method == "" {
+		return "btree"
+	}
+	return strings.ToLower(method)
+}
+
+// indexKeyExpression strips COLLATE and ASC/DESC from an index key.
+func indexKeyExpression(key string) string {
+	upper := strings.ToUpper(key)
+	if i := strings.Index(upper, " COLLATE "); i >= 0 {
+		key, upper = key[:i], upper[:i]
+	}
+	for _, suffix := range []string{" ASC", " DESC"} {
+		if strings.HasSuffix(upper, suffix) {
+			key = key[:len(key)-len(suffix)]
+		}
+	}
+	return key
+}
+
+// literalCast matches a string literal with the type cast Postgres adds
+// when it deparses an expression, e.g. 'a'::text.
+var literalCast = regexp.MustCompile(`('(?:[^']|'')*')::[a-z_]+(\[\])?`)
+
+// normalizeExpression makes SQL expressions comparable by dropping
+// whitespace, case, literal casts and the outer parentheses Postgres adds
+// when it deparses them. String literals are kept as written.
+func normalizeExpression(expr string) string {
+	var b strings.Builder
+	inLiteral := false
+	for _, r := range expr {
+		switch {
+		case r == '\'':
+			// An escaped quote toggles twice and stays inside the literal.
+			inLiteral = !inLiteral
+			b.WriteRune(r)
+		case inLiteral:
+			b.WriteRune(r)
+		case !unicode.IsSpace(r):
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	expr = literalCast.ReplaceAllString(b.String(), "$1")
+	for len(expr) >= 2 && expr[0] == '(' && expr[len(expr)-1] == ')' && balanced(expr[1:len(expr)-1]) {
+		expr = expr[1 : len(expr)-1]
+	}
+	return expr
+}
+
+func balanced(expr string) bool {
+	depth := 0
+	for _, c := range expr {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
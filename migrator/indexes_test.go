@@ -0,0 +1,25 @@
+// File: migrator/indexes_test.go
+
+package migrator
+
+import "testing"
+
+func TestNormalizeExpression(t *testing.T) {
+	tests := []struct {
+		model, live string
+		want        bool
+	}{
+		{"status = 'active'", "(status = 'active'::text)", true},
+		{"Deleted_At IS NULL", "(deleted_at IS NULL)", true},
+		{"status IN ('A')", "(status IN ('a'))", false},
+		{"name <> 'a b'", "(name <> 'ab'::text)", false},
+		{"name <> 'it''s'", "(name <> 'it''s'::text)", true},
+		{"name <> 'IT''S X'", "(name <> 'it''s x'::text)", false},
+		{"(a > 0) AND (b > 0)", "((a > 0) AND (b > 0))", true},
+	}
+	for _, tt := range tests {
+		if got := normalizeExpression(tt.model) == normalizeExpression(tt.live); got != tt.want {
+			t.Errorf("normalizeExpression(%q) == normalizeExpression(%q) is %v, want %v", tt.model, tt.live, got, tt.want)
+		}
+	}
+}
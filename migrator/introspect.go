@@ -0,0 +1,213 @@
+// File: migrator/introspect.go
+
+package migrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// The introspection queries read pg_catalog directly and load every table
+// in the current schema at once, instead of querying per column.
+const (
+	introspectTablesSQL = `
+SELECT c.relname, COALESCE(obj_description(c.oid, 'pg_class'), '')
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = current_schema() AND c.relkind IN ('r', 'p')`
+
+	introspectColumnsSQL = `
+SELECT c.relname, a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull,
+	COALESCE(pg_get_expr(d.adbin, d.adrelid), ''),
+	COALESCE(col_description(c.oid, a.attnum), '')
+FROM pg_attribute a
+JOIN pg_class c ON c.oid = a.attrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE n.nspname = current_schema() AND c.relkind IN ('r', 'p') AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY c.relname, a.attnum`
+
+	introspectConstraintsSQL = `
+SELECT c.relname, con.conname, con.contype::text,
+	COALESCE((SELECT json_agg(a.attname ORDER BY k.ord)
+		FROM unnest(con.conkey) WITH ORDINALITY k(attnum, ord)
+		JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum), '[]')::text,
+	COALESCE(rc.relname, ''),
+	COALESCE((SELECT json_agg(a.attname ORDER BY k.ord)
+		FROM unnest(con.confkey) WITH ORDINALITY k(attnum, ord)
+		JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum), '[]')::text,
+	con.confupdtype::text, con.confdeltype::text,
+	COALESCE(pg_get_expr(con.conbin, con.conrelid), '')
+FROM pg_constraint con
+JOIN pg_class c ON c.oid = con.conrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_class rc ON rc.oid = con.confrelid
+WHERE n.nspname = current_schema() AND con.contype IN ('p', 'u', 'f', 'c')
+ORDER BY c.relname, con.conname`
+
+	introspectIndexesSQL = `
+SELECT c.relname, i.relname, ix.indisunique, ix.indisprimary,
+	EXISTS (SELECT 1 FROM pg_constraint con WHERE con.conindid = ix.indexrelid),
+	am.amname,
+	(SELECT json_agg(pg_get_indexdef(ix.indexrelid, k, true) ORDER BY k)
+		FROM generate_series(1, ix.indnkeyatts) k)::text,
+	COALESCE(pg_get_expr(ix.indpred, ix.indrelid, true), '')
+FROM pg_index ix
+JOIN pg_class i ON i.oid = ix.indexrelid
+JOIN pg_class c ON c.oid = ix.indrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_am am ON am.oid = i.relam
+WHERE n.nspname = current_schema()
+ORDER BY c.relname, i.relname`
+)
+
+// foreignKeyActions maps pg_constraint action codes to SQL. NO ACTION is
+// the default and is left empty.
+var foreignKeyActions = map[string]string{
+	"a": "",
+	"r": "RESTRICT",
+	"c": "CASCADE",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
+}
+
+// InspectSchema loads every table in the current schema, together with its
+// columns, primary key, constraints, indexes and comments.
+func (m *Migrator) InspectSchema(ctx context.Context) (*Schema, error) {
+	schema := &Schema{Tables: make(map[string]*Table)}
+
+	steps := []struct {
+		what string
+		load func(context.Context, *Schema) error
+	}{
+		{"tables", m.inspectTables},
+		{"columns", m.inspectColumns},
+		{"constraints", m.inspectConstraints},
+		{"indexes", m.inspectIndexes},
+	}
+	for _, step := range steps {
+		if err := step.load(ctx, schema); err != nil {
+			return nil, fmt.Errorf("failed to introspect %s: %v", step.what, err)
+		}
+	}
+
+	if m.config.Debug {
+		fmt.Printf("Introspected %d tables\n", len(schema.Tables))
+	}
+	return schema, nil
+}
+
+func (m *Migrator) inspectTables(ctx context.Context, schema *Schema) error {
+	rows, err := m.sqlDB.QueryContext(ctx, introspectTablesSQL)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		table := &Table{}
+		if err := rows.Scan(&table.Name, &table.Comment); err != nil {
+			return err
+		}
+		schema.Tables[table.Name] = table
+	}
+	return rows.Err()
+}
+
+func (m *Migrator) inspectColumns(ctx context.Context, schema *Schema) error {
+	rows, err := m.sqlDB.QueryContext(ctx, introspectColumnsSQL)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName string
+		column := &Column{}
+		err := rows.Scan(&tableName, &column.Name, &column.Type, &column.NotNull, &column.Default, &column.Comment)
+		if err != nil {
+			return err
+		}
+		column.AutoIncrement = strings.HasPrefix(column.Default, "nextval(")
+		if table := schema.Table(tableName); table != nil {
+			table.Columns = append(table.Columns, column)
+		}
+	}
+	return rows.Err()
+}
+
+func (m *Migrator) inspectConstraints(ctx context.Context, schema *Schema) error {
+	rows, err := m.sqlDB.QueryContext(ctx, introspectConstraintsSQL)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, name, kind, columnsJSON, refTable, refColumnsJSON, onUpdate, onDelete, definition string
+		err := rows.Scan(&tableName, &name, &kind, &columnsJSON, &refTable, &refColumnsJSON, &onUpdate, &onDelete, &definition)
+		if err != nil {
+			return err
+		}
+		table := schema.Table(tableName)
+		if table == nil {
+			continue
+		}
+
+		var columns, refColumns []string
+		if err := json.Unmarshal([]byte(columnsJSON), &columns); err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(refColumnsJSON), &refColumns); err != nil {
+			return err
+		}
+
+		switch kind {
+		case "p":
+			table.PrimaryKey = &Constraint{Name: name, Columns: columns, Primary: true}
+		case "u":
+			table.Uniques = append(table.Uniques, Constraint{Name: name, Columns: columns})
+		case "c":
+			table.Checks = append(table.Checks, Constraint{Name: name, Definition: definition})
+		case "f":
+			table.ForeignKeys = append(table.ForeignKeys, ForeignKey{
+				Name:       name,
+				Columns:    columns,
+				RefTable:   refTable,
+				RefColumns: refColumns,
+				OnUpdate:   foreignKeyActions[onUpdate],
+				OnDelete:   foreignKeyActions[onDelete],
+			})
+		}
+	}
+	return rows.Err()
+}
+
+func (m *Migrator) inspectIndexes(ctx context.Context, schema *Schema) error {
+	rows, err := m.sqlDB.QueryContext(ctx, introspectIndexesSQL)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, columnsJSON string
+		var index Index
+		err := rows.Scan(&tableName, &index.Name, &index.Unique, &index.Primary, &index.Constraint,
+			&index.Method, &columnsJSON, &index.Where)
+		if err != nil {
+			return err
+		}
+		table := schema.Table(tableName)
+		if table == nil {
+			continue
+		}
+		if err := json.Unmarshal([]byte(columnsJSON), &index.Columns); err != nil {
+			return err
+		}
+		table.Indexes = append(table.Indexes, index)
+	}
+	return rows.Err()
+}
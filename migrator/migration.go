@@ -0,0 +1,171 @@
+// File: migrator/migration.go
+
+package migrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const versionFormat = "20060102150405"
+
+// noTransactionDirective, as the first line of a migration file, makes the
+// file run outside a transaction, one statement at a time. Statements such
+// as CREATE INDEX CONCURRENTLY fail inside a transaction block.
+const noTransactionDirective = "-- go-migrator:no-transaction"
+
+// Migration is a pair of up and down scripts that share one version and
+// one name. They are always written and read together.
+type Migration struct {
+	Version string
+	Name    string
+	Up      string
+	Down    string
+}
+
+func (mig Migration) filename(direction string) string {
+	return fmt.Sprintf("%s_%s.%s.sql", mig.Version, mig.Name, direction)
+}
+
+// inTransaction reports whether script runs inside a transaction, i.e. it
+// does not start with noTransactionDirective.
+func inTransaction(script string) bool {
+	return !strings.HasPrefix(strings.TrimSpace(script), noTransactionDirective)
+}
+
+// splitStatements splits script at the semicolons that end its statements,
+// ignoring those inside quotes and -- comments. Dollar-quoted bodies are not
+// recognised, so a function definition belongs in a migration that runs in
+// a transaction.
+func splitStatements(script string) []string {
+	var statements []string
+	start := 0
+	var quote byte
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '-' && i+1 < len(script) && script[i+1] == '-':
+			if end := strings.IndexByte(script[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(script)
+			}
+		case c == ';':
+			statements = appendStatement(statements, script[start:i])
+			start = i + 1
+		}
+	}
+	return appendStatement(statements, script[start:])
+}
+
+// appendStatement appends stmt unless it holds nothing but comments and
+// whitespace.
+func appendStatement(statements []string, stmt string) []string {
+	for _, line := range strings.Split(stmt, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return append(statements, strings.TrimSpace(stmt))
+		}
+	}
+	return statements
+}
+
+// newMigration returns a migration with a version that is strictly greater
+// than any version handed out earlier by this Migrator and than any
+// migration already in the output directory, so that models processed
+// within the same second, or two runs within one, get distinct versions.
+func (m *Migrator) newMigration(name, up, down string) Migration {
+	last := m.lastVersion
+	if latest := latestVersion(m.config.OutputDir); latest.After(last) {
+		last = latest
+	}
+	now := time.Now().Truncate(time.Second)
+	if !now.After(last) {
+		now = last.Add(time.Second)
+	}
+	m.lastVersion = now
+
+	return Migration{
+		Version: now.Format(versionFormat),
+		Name:    name,
+		Up:      up,
+		Down:    down,
+	}
+}
+
+// writeMigration writes the up and down files of mig to the output
+// directory. Either both files are created or neither is.
+func (m *Migrator) writeMigration(mig Migration) error {
+	if mig.Up == "" || mig.Down == "" {
+		return fmt.Errorf("migration %s must have both up and down SQL", mig.Name)
+	}
+
+	err := os.MkdirAll(m.config.OutputDir, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("failed to create migrations directory: %v", err)
+	}
+
+	upPath := filepath.Join(m.config.OutputDir, mig.filename("up"))
+	downPath := filepath.Join(m.config.OutputDir, mig.filename("down"))
+	for _, path := range []string{upPath, downPath} {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("migration file %s already exists", path)
+		}
+	}
+
+	upTmp, err := writeTempFile(m.config.OutputDir, mig.Up)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(upTmp)
+
+	downTmp, err := writeTempFile(m.config.OutputDir, mig.Down)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(downTmp)
+
+	if err := os.Rename(upTmp, upPath); err != nil {
+		return fmt.Errorf("failed to write migration file: %v", err)
+	}
+	if err := os.Rename(downTmp, downPath); err != nil {
+		os.Remove(upPath)
+		return fmt.Errorf("failed to write migration file: %v", err)
+	}
+
+	fmt.Printf("Created migration: %s\n", filepath.Join(m.config.OutputDir, mig.Version+"_"+mig.Name))
+	if m.config.Debug {
+		fmt.Printf("Up:\n%s\nDown:\n%s\n", mig.Up, mig.Down)
+	}
+	return nil
+}
+
+func writeTempFile(dir, content string) (string, error) {
+	f, err := os.CreateTemp(dir, ".migration-*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create migration file: %v", err)
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write migration file: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write migration file: %v", err)
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write migration file: %v", err)
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,122 @@
+// File: migrator/migration_files.go
+
+package migrator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// migrationFilePattern matches files written by writeMigration,
+// e.g. 20240102150405_create_users_table.up.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// readMigrations loads the migrations in dir, pairing up and down files by
+// version, sorted by their timestamp prefix.
+func readMigrations(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read migrations directory: %v", err)
+	}
+
+	byVersion := make(map[string]*Migration)
+	hasUp := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, name, direction := match[1], match[2], match[3]
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: name}
+			byVersion[version] = mig
+		} else if mig.Name != name {
+			return nil, fmt.Errorf("migration version %s is used by both %s and %s", version, mig.Name, name)
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration file %s: %v", entry.Name(), err)
+		}
+		if direction == "up" {
+			mig.Up = string(content)
+			hasUp[version] = true
+		} else {
+			mig.Down = string(content)
+		}
+	}
+
+	var migrations []Migration
+	for version, mig := range byVersion {
+		if !hasUp[version] {
+			log.Printf("Ignoring down migration without a matching up file: %s", mig.filename("down"))
+			continue
+		}
+		migrations = append(migrations, *mig)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return compareVersions(migrations[i].Version, migrations[j].Version) < 0
+	})
+	return migrations, nil
+}
+
+// latestVersion returns the time of the newest migration in dir, or the
+// zero time if there is none. Versions that are not timestamps are ignored.
+func latestVersion(dir string) time.Time {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return time.Time{}
+	}
+
+	var latest time.Time
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := time.ParseInLocation(versionFormat, match[1], time.Local)
+		if err == nil && version.After(latest) {
+			latest = version
+		}
+	}
+	return latest
+}
+
+// compareVersions orders numeric version strings without parsing them,
+// so prefixes longer than an int64 still sort correctly.
+func compareVersions(a, b string) int {
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
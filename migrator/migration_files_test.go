@@ -0,0 +1,100 @@
+// File: migrator/migration_files_test.go
+
+package migrator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    []Migration
+		wantErr string
+	}{
+		{
+			name: "pairs up and down files in version order",
+			files: map[string]string{
+				"20240102000000_add_email.up.sql":             "up 2",
+				"20240102000000_add_email.down.sql":           "down 2",
+				"20240101000000_create_users.up.sql":          "up 1",
+				"20240101000000_create_users.down.sql":        "down 1",
+				"20240103000000_no_down_file.up.sql":          "up 3",
+				"README.md":                                   "not a migration",
+				"20240104000000_missing_direction.sql":        "not a migration",
+				"20240105000000_wrong_direction.sideways.sql": "not a migration",
+			},
+			want: []Migration{
+				{Version: "20240101000000", Name: "create_users", Up: "up 1", Down: "down 1"},
+				{Version: "20240102000000", Name: "add_email", Up: "up 2", Down: "down 2"},
+				{Version: "20240103000000", Name: "no_down_file", Up: "up 3"},
+			},
+		},
+		{
+			name: "down without an up is ignored",
+			files: map[string]string{
+				"20240101000000_create_users.up.sql": "up 1",
+				"20240102000000_orphan.down.sql":     "down 2",
+			},
+			want: []Migration{
+				{Version: "20240101000000", Name: "create_users", Up: "up 1"},
+			},
+		},
+		{
+			name: "one version used by two names",
+			files: map[string]string{
+				"20240101000000_create_users.up.sql":  "up",
+				"20240101000000_create_orders.up.sql": "up",
+			},
+			wantErr: "migration version 20240101000000 is used by both",
+		},
+		{
+			name: "one version used by two names in different directions",
+			files: map[string]string{
+				"20240101000000_create_users.up.sql":    "up",
+				"20240101000000_create_orders.down.sql": "down",
+			},
+			wantErr: "migration version 20240101000000 is used by both",
+		},
+		{
+			name:  "empty directory",
+			files: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := readMigrations(dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readMigrations() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readMigrations() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMigrationsMissingDirectory(t *testing.T) {
+	got, err := readMigrations(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || got != nil {
+		t.Errorf("readMigrations() = %v, %v, want no migrations and no error", got, err)
+	}
+}
@@ -0,0 +1,86 @@
+// File: migrator/migration_test.go
+
+package migrator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "one per line",
+			script: "CREATE INDEX CONCURRENTLY a ON t (x);\nCREATE INDEX CONCURRENTLY b ON t (y);",
+			want:   []string{"CREATE INDEX CONCURRENTLY a ON t (x)", "CREATE INDEX CONCURRENTLY b ON t (y)"},
+		},
+		{
+			name:   "directive and trailing comment",
+			script: noTransactionDirective + "\nDROP INDEX CONCURRENTLY IF EXISTS a;\n-- done\n",
+			want:   []string{noTransactionDirective + "\nDROP INDEX CONCURRENTLY IF EXISTS a"},
+		},
+		{
+			name:   "semicolons in quotes and comments",
+			script: "CREATE INDEX a ON t (x) WHERE y <> ';' AND \"z;\" > 0; -- x; y\nSELECT 1",
+			want:   []string{"CREATE INDEX a ON t (x) WHERE y <> ';' AND \"z;\" > 0", "-- x; y\nSELECT 1"},
+		},
+		{
+			name:   "escaped quote",
+			script: "SELECT 'it''s; fine';",
+			want:   []string{"SELECT 'it''s; fine'"},
+		},
+		{
+			name:   "empty",
+			script: "\n  \n",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements(%q) = %q, want %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMigrationAfterExistingVersions(t *testing.T) {
+	dir := t.TempDir()
+	future := time.Now().Add(time.Hour).Truncate(time.Second)
+	for _, name := range []string{
+		future.Format(versionFormat) + "_create_users_table.up.sql",
+		future.Format(versionFormat) + "_create_users_table.down.sql",
+		"0001_legacy.up.sql",
+		"notes.txt",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := &Migrator{config: Config{OutputDir: dir}}
+	first := m.newMigration("a", "", "")
+	second := m.newMigration("b", "", "")
+	if want := future.Add(time.Second).Format(versionFormat); first.Version != want {
+		t.Errorf("first version = %s, want %s", first.Version, want)
+	}
+	if compareVersions(second.Version, first.Version) <= 0 {
+		t.Errorf("second version %s is not after %s", second.Version, first.Version)
+	}
+
+	// Another Migrator writing to the same directory starts after them.
+	if err := m.writeMigration(Migration{Version: second.Version, Name: "b", Up: "SELECT 1;", Down: "SELECT 1;"}); err != nil {
+		t.Fatal(err)
+	}
+	other := &Migrator{config: Config{OutputDir: dir}}
+	if next := other.newMigration("c", "", ""); compareVersions(next.Version, second.Version) <= 0 {
+		t.Errorf("version %s of a new run is not after %s", next.Version, second.Version)
+	}
+}
@@ -0,0 +1,174 @@
+// File: migrator/migrator.go
+
+// Package migrator generates SQL migrations by diffing GORM models against
+// a live PostgreSQL schema, and applies or rolls them back.
+//
+// A service can run generation from its own entry point, such as
+// tools/migrate/main.go:
+//
+//	m, err := migrator.New(migrator.Config{DBUser: "app", DBName: "app", OutputDir: "migrations"})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	m.AddModel(&models.User{})
+//	if err := m.GenerateMigrations(); err != nil {
+//		log.Fatal(err)
+//	}
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Migrator struct {
+	config Config
+	db     *gorm.DB
+	sqlDB  *sql.DB
+	models *Registry
+
+	dialect     Dialect
+	typeMappers []TypeMapper
+
+	lastVersion time.Time
+}
+
+func New(config Config) (*Migrator, error) {
+	dsn, err := config.DSN()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database: %v", err)
+	}
+
+	m := &Migrator{
+		config:  config,
+		db:      db,
+		sqlDB:   sqlDB,
+		models:  NewRegistry(),
+		dialect: postgresDialect{dialector: db.Dialector},
+	}
+	m.models.Register(RegisteredModels()...)
+	return m, nil
+}
+
+// AddModel adds a model to this migrator. A model whose type was already
+// added or registered globally is ignored.
+func (m *Migrator) AddModel(model interface{}) {
+	m.models.Register(model)
+}
+
+// AddModels adds several models at once, in order.
+func (m *Migrator) AddModels(models ...interface{}) {
+	m.models.Register(models...)
+}
+
+func (m *Migrator) GenerateMigrations() error {
+	// Models registered globally after New are picked up as well.
+	m.models.Register(RegisteredModels()...)
+	if len(m.models.Models()) == 0 {
+		return fmt.Errorf("no models registered; add them with RegisterModel or AddModel")
+	}
+
+	schema, err := m.InspectSchema(context.Background())
+	if err != nil {
+		return err
+	}
+
+	infos, err := m.parseModels()
+	if err != nil {
+		return err
+	}
+
+	created := make(map[string]bool)
+	for _, info := range infos {
+		tableName := info.Table
+
+		if m.config.Debug {
+			log.Printf("Processing model: %s", info.Schema.Name)
+			info.logFieldDecisions()
+		}
+
+		table := schema.Table(tableName)
+		if table == nil {
+			// Table doesn't exist, create a new migration to create the table
+			createSQL := m.generateCreateTableSQL(info)
+			if createSQL == "" {
+				log.Printf("Failed to generate CREATE TABLE SQL for %s", tableName)
+				continue
+			}
+			changes := append([]SchemaChange{CreateTable{Table: tableName, SQL: createSQL}}, m.createIndexChanges(info)...)
+			if err := m.writeChanges(fmt.Sprintf("create_%s_table", tableName), changes); err != nil {
+				return err
+			}
+			created[tableName] = true
+		} else {
+			// Table exists, check for differences and create migration if needed
+			changes := m.compareModelToTable(info, table)
+			changes = append(changes, m.compareConstraints(info, table)...)
+			changes = append(changes, m.compareIndexes(info, table)...)
+			if len(changes) > 0 {
+				if err := m.writeChanges(fmt.Sprintf("alter_%s_table", tableName), changes); err != nil {
+					return err
+				}
+			} else if m.config.Debug {
+				log.Printf("No differences found for table %s", tableName)
+			}
+		}
+	}
+
+	// Foreign keys go into their own migration so that every table they
+	// reference has been created first.
+	if changes := m.compareForeignKeys(infos, schema, created); len(changes) > 0 {
+		mig := m.newMigration("add_foreign_keys", renderUp(m.dialect, changes), renderDown(m.dialect, changes))
+		if err := m.writeMigration(mig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeChanges writes changes as a migration called name. Postgres refuses
+// to build or drop an index CONCURRENTLY inside a transaction block, so
+// such index changes go into a second migration, name_concurrently, that is
+// applied without one.
+func (m *Migrator) writeChanges(name string, changes []SchemaChange) error {
+	var transactional, concurrent []SchemaChange
+	for _, c := range changes {
+		if isConcurrentChange(c) {
+			concurrent = append(concurrent, c)
+		} else {
+			transactional = append(transactional, c)
+		}
+	}
+
+	if len(transactional) > 0 {
+		mig := m.newMigration(name, renderUp(m.dialect, transactional), renderDown(m.dialect, transactional))
+		if err := m.writeMigration(mig); err != nil {
+			return err
+		}
+	}
+	if len(concurrent) > 0 {
+		mig := m.newMigration(name+"_concurrently",
+			noTransactionDirective+"\n"+renderUp(m.dialect, concurrent),
+			noTransactionDirective+"\n"+renderDown(m.dialect, concurrent))
+		if err := m.writeMigration(mig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+// File: migrator/migrator_test.go
+
+package migrator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateMigrationsWithoutModels(t *testing.T) {
+	m := newTestMigrator(t)
+	m.config.OutputDir = t.TempDir()
+	err := m.GenerateMigrations()
+	if err == nil || !strings.Contains(err.Error(), "no models registered") {
+		t.Fatalf("GenerateMigrations() error = %v, want no models registered", err)
+	}
+}
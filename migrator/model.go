@@ -0,0 +1,143 @@
+// File: migrator/model.go
+
+package migrator
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// modelInfo is GORM's own metadata for a registered model, so the generated
+// DDL uses the same table and column names GORM uses at runtime.
+type modelInfo struct {
+	Table  string
+	Schema *schema.Schema
+}
+
+func (m *Migrator) parseModel(model interface{}) (*modelInfo, error) {
+	stmt := &gorm.Statement{DB: m.db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, fmt.Errorf("failed to parse model %T: %v", model, err)
+	}
+	return &modelInfo{Table: stmt.Table, Schema: stmt.Schema}, nil
+}
+
+// columnFields returns the fields GORM persists as columns, in
+// declaration order. Fields of embedded structs, including `embedded`
+// fields with an `embeddedPrefix`, are already flattened by GORM at any
+// depth.
+func (info *modelInfo) columnFields() []*schema.Field {
+	var fields []*schema.Field
+	for _, field := range info.Schema.Fields {
+		if include, _ := info.classifyField(field); include {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// classifyField decides whether field becomes a column and explains why.
+// Associations, ignored fields and fields GORM never writes are skipped.
+func (info *modelInfo) classifyField(field *schema.Field) (bool, string) {
+	if rel, ok := info.Schema.Relationships.Relations[field.Name]; ok {
+		return false, fmt.Sprintf("%s association", rel.Type)
+	}
+	if field.IgnoreMigration {
+		return false, "ignored for migrations"
+	}
+	if field.DBName == "" {
+		if _, ok := field.TagSettings["-"]; ok {
+			return false, "ignored by `-` tag"
+		}
+		return false, "not mapped to a column"
+	}
+	if winner := info.Schema.FieldsByDBName[field.DBName]; winner != nil && winner != field {
+		return false, fmt.Sprintf("column %s is already provided by %s", field.DBName, winner.BindName())
+	}
+	if !field.Creatable && !field.Updatable {
+		if strings.EqualFold(strings.TrimSpace(field.TagSettings["<-"]), "false") {
+			return false, "writes disabled by `<-:false`"
+		}
+		return false, "read-only `->` field"
+	}
+	return true, fmt.Sprintf("column %s", field.DBName)
+}
+
+// logFieldDecisions prints how every field of the model was classified,
+// including unexported fields, which GORM does not parse at all.
+func (info *modelInfo) logFieldDecisions() {
+	for _, field := range info.Schema.Fields {
+		include, reason := info.classifyField(field)
+		action := "skipped"
+		if include {
+			action = "included"
+		}
+		log.Printf("  %s.%s: %s, %s", info.Schema.Name, field.BindName(), action, reason)
+	}
+
+	modelType := info.Schema.ModelType
+	for i := 0; i < modelType.NumField(); i++ {
+		if structField := modelType.Field(i); !structField.IsExported() && !structField.Anonymous {
+			log.Printf("  %s.%s: skipped, unexported", info.Schema.Name, structField.Name)
+		}
+	}
+}
+
+// joinTables returns the join tables of the model's many2many
+// relationships, using the schema GORM builds for each of them so that
+// custom joinForeignKey and joinReferences names are honored.
+func (info *modelInfo) joinTables() []*modelInfo {
+	names := make([]string, 0, len(info.Schema.Relationships.Many2Many))
+	byName := make(map[string]*schema.Relationship)
+	for _, rel := range info.Schema.Relationships.Many2Many {
+		if rel.JoinTable == nil || rel.Field.IgnoreMigration {
+			continue
+		}
+		names = append(names, rel.Name)
+		byName[rel.Name] = rel
+	}
+	sort.Strings(names)
+
+	tables := make([]*modelInfo, 0, len(names))
+	for _, name := range names {
+		joinTable := byName[name].JoinTable
+		tables = append(tables, &modelInfo{Table: joinTable.Table, Schema: joinTable})
+	}
+	return tables
+}
+
+// parseModels parses every registered model and adds the join tables of
+// their many2many relationships. Tables are de-duplicated by name, since
+// both sides of a relationship usually declare the same join table.
+func (m *Migrator) parseModels() ([]*modelInfo, error) {
+	var infos []*modelInfo
+	seen := make(map[string]bool)
+	add := func(info *modelInfo) {
+		if !seen[info.Table] {
+			seen[info.Table] = true
+			infos = append(infos, info)
+		}
+	}
+
+	for _, model := range m.models.Models() {
+		info, err := m.parseModel(model)
+		if err != nil {
+			return nil, err
+		}
+		add(info)
+	}
+
+	// Join tables are added after every model has been parsed, so a join
+	// model registered explicitly takes precedence over the generated one.
+	for _, info := range append([]*modelInfo(nil), infos...) {
+		for _, joinTable := range info.joinTables() {
+			add(joinTable)
+		}
+	}
+	return infos, nil
+}
@@ -0,0 +1,69 @@
+// File: migrator/model_comparison.go
+
+package migrator
+
+import (
+	"strconv"
+	"strings"
+)
+
+// compareModelToTable diffs a model against its live table, as loaded by
+// InspectSchema.
+func (m *Migrator) compareModelToTable(info *modelInfo, table *Table) []SchemaChange {
+	var changes []SchemaChange
+
+	for _, field := range info.columnFields() {
+		columnName := field.DBName
+		column := m.buildColumn(field)
+
+		live := table.Column(columnName)
+		if live == nil {
+			changes = append(changes, AddColumn{Table: table.Name, Column: column, Backfill: backfillValue(field, column)})
+			continue
+		}
+
+		if !sameColumnType(m.dialect, column, *live) {
+			changes = append(changes, AlterColumnType{Table: table.Name, Column: columnName, From: live.Type, To: column.Type})
+		}
+
+		// Nullability drift: a `not null` tag that was added or removed.
+		if column.NotNull && !live.NotNull {
+			changes = append(changes, SetNotNull{Table: table.Name, Column: columnName, NotNull: true})
+		} else if !column.NotNull && live.NotNull && !field.PrimaryKey {
+			changes = append(changes, SetNotNull{Table: table.Name, Column: columnName, NotNull: false})
+		}
+
+		if column.Comment != live.Comment {
+			changes = append(changes, SetComment{Table: table.Name, Column: columnName, Comment: column.Comment, Previous: live.Comment})
+		}
+
+		// Serial columns get their nextval() default from the sequence, so
+		// their default is left alone.
+		if !column.AutoIncrement && !live.AutoIncrement && !sameDefault(column.Default, live.Default) {
+			changes = append(changes, SetDefault{Table: table.Name, Column: columnName, Default: column.Default, Previous: live.Default})
+		}
+	}
+
+	return changes
+}
+
+// sameDefault compares a model default with a live one as Postgres
+// deparses it, so 'active' matches 'active'::character varying and -1
+// matches '-1'::integer, while the contents of string literals must match
+// exactly. A `default:null` is the same as no default.
+func sameDefault(model, live string) bool {
+	return normalizeDefault(model) == normalizeDefault(live)
+}
+
+func normalizeDefault(value string) string {
+	value = normalizeExpression(value)
+	if value == "null" || strings.HasPrefix(value, "null::") {
+		return ""
+	}
+	if unquoted := strings.Trim(value, "'"); len(unquoted) == len(value)-2 {
+		if _, err := strconv.ParseFloat(unquoted, 64); err == nil {
+			return unquoted
+		}
+	}
+	return value
+}
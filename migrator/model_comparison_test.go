@@ -0,0 +1,75 @@
+// File: migrator/model_comparison_test.go
+
+package migrator
+
+import "testing"
+
+func TestSameDefault(t *testing.T) {
+	tests := []struct {
+		model, live string
+		want        bool
+	}{
+		{"", "", true},
+		{"'active'", "'active'::character varying", true},
+		{"'active'", "'active'::text", true},
+		{"'active'", "'inactive'::text", false},
+		{"'Pending'", "'pending'::text", false},
+		{"'a b'", "'ab'::text", false},
+		{"'a b'", "'a b'::text", true},
+		{"-1", "'-1'::integer", true},
+		{"0", "0", true},
+		{"1.5", "1.5", true},
+		{"true", "true", true},
+		{"TRUE", "true", true},
+		{"now()", "now()", true},
+		{"CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP", true},
+		{"null", "", true},
+		{"", "NULL::character varying", true},
+		{"", "0", false},
+		{"0", "", false},
+	}
+	for _, tt := range tests {
+		if got := sameDefault(tt.model, tt.live); got != tt.want {
+			t.Errorf("sameDefault(%q, %q) = %v, want %v", tt.model, tt.live, got, tt.want)
+		}
+	}
+}
+
+type defaultModel struct {
+	ID      uint
+	Status  string `gorm:"default:'active'"`
+	Retries int    `gorm:"default:3"`
+	Name    string
+	Score   int `gorm:"default:-1"`
+}
+
+func TestCompareDefaults(t *testing.T) {
+	m := newTestMigrator(t)
+	info := parseTestModel(t, m, &defaultModel{})
+	live := &Table{
+		Name: "default_models",
+		Columns: []*Column{
+			{Name: "id", Type: "bigint", NotNull: true, Default: "nextval('default_models_id_seq'::regclass)", AutoIncrement: true},
+			{Name: "status", Type: "text", Default: "'pending'::text"},
+			{Name: "retries", Type: "bigint"},
+			{Name: "name", Type: "text", Default: "''::text"},
+			{Name: "score", Type: "bigint", Default: "'-1'::integer"},
+		},
+	}
+
+	changes := m.compareModelToTable(info, live)
+
+	wantUp := `ALTER TABLE "default_models" ALTER COLUMN "status" SET DEFAULT 'active';
+ALTER TABLE "default_models" ALTER COLUMN "retries" SET DEFAULT 3;
+ALTER TABLE "default_models" ALTER COLUMN "name" DROP DEFAULT;`
+	if got := renderUp(m.dialect, changes); got != wantUp {
+		t.Errorf("up =\n%s\nwant\n%s", got, wantUp)
+	}
+
+	wantDown := `ALTER TABLE "default_models" ALTER COLUMN "name" SET DEFAULT ''::text;
+ALTER TABLE "default_models" ALTER COLUMN "retries" DROP DEFAULT;
+ALTER TABLE "default_models" ALTER COLUMN "status" SET DEFAULT 'pending'::text;`
+	if got := renderDown(m.dialect, changes); got != wantDown {
+		t.Errorf("down =\n%s\nwant\n%s", got, wantDown)
+	}
+}
@@ -0,0 +1,396 @@
+// File: migrator/postgres.go
+
+package migrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// postgresDialect is the Dialect for PostgreSQL. Types it has no special
+// handling for come from the GORM dialector, so generated columns match
+// the ones AutoMigrate creates.
+type postgresDialect struct {
+	dialector gorm.Dialector
+}
+
+var jsonRawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// DataType uses an explicit `type` tag, GormDataType, well-known types
+// such as UUIDs and arrays, and finally the GORM dialector's type for the
+// field, in that order. An explicit type gets `size`, `precision` and
+// `scale` applied when it has no modifiers of its own.
+func (d postgresDialect) DataType(field *schema.Field) string {
+	if _, ok := field.TagSettings["TYPE"]; ok && !isGenericDataType(field.DataType) {
+		return withTypeModifiers(string(field.DataType), field)
+	}
+	if !isGenericDataType(field.DataType) {
+		// A GormDataType of "json" names the kind of data rather than a
+		// column type, so it gets the indexable jsonb.
+		if strings.EqualFold(string(field.DataType), "json") {
+			return "JSONB"
+		}
+		return withTypeModifiers(string(field.DataType), field)
+	}
+	if field.Serializer == nil {
+		if typ := knownType(field.IndirectFieldType); typ != "" {
+			return typ
+		}
+	}
+	return d.dialector.DataTypeOf(field)
+}
+
+// isGenericDataType reports whether t is one of GORM's own data types
+// rather than a database type.
+func isGenericDataType(t schema.DataType) bool {
+	switch t {
+	case schema.Int, schema.Uint, schema.String, schema.Bool, schema.Float, schema.Time, schema.Bytes, "":
+		return true
+	}
+	return false
+}
+
+// knownType maps common types GORM has no data type for, or maps to the
+// wrong one: UUIDs such as uuid.UUID, which GORM sees as bytes,
+// json.RawMessage, and slices like []string or pq.StringArray. GORM only
+// accepts such slices when they implement GormDataType or have a `type`
+// tag.
+func knownType(t reflect.Type) string {
+	switch {
+	case t == jsonRawMessageType:
+		return "JSONB"
+	case strings.EqualFold(t.Name(), "UUID") && (t.Kind() == reflect.String || t.Kind() == reflect.Array && t.Len() == 16):
+		return "UUID"
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8:
+		if elem := arrayElementType(t.Elem()); elem != "" {
+			return elem + "[]"
+		}
+	}
+	return ""
+}
+
+func arrayElementType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "TEXT"
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return "BIGINT"
+	case reflect.Int32, reflect.Uint16:
+		return "INTEGER"
+	case reflect.Int8, reflect.Int16, reflect.Uint8:
+		return "SMALLINT"
+	case reflect.Float32:
+		return "REAL"
+	case reflect.Float64:
+		return "DOUBLE PRECISION"
+	case reflect.Bool:
+		return "BOOLEAN"
+	}
+	return ""
+}
+
+// withTypeModifiers adds the field's size, or precision and scale, to an
+// explicit type such as "varchar" or "numeric".
+func withTypeModifiers(typ string, field *schema.Field) string {
+	if strings.Contains(typ, "(") {
+		return typ
+	}
+	switch canonicalTypeName(typ) {
+	case "character varying", "character":
+		if field.Size > 0 {
+			return fmt.Sprintf("%s(%d)", typ, field.Size)
+		}
+	case "numeric":
+		if field.Precision > 0 {
+			return numericType(typ, field.Precision, field.Scale)
+		}
+	}
+	return typ
+}
+
+func numericType(typ string, precision, scale int) string {
+	if scale > 0 {
+		return fmt.Sprintf("%s(%d,%d)", typ, precision, scale)
+	}
+	return fmt.Sprintf("%s(%d)", typ, precision)
+}
+
+// typeAliases maps the spellings Postgres accepts for a built-in type to
+// the name format_type reports, so "VARCHAR", "int8" and "TIMESTAMP" compare
+// equal to "character varying", "bigint" and "timestamp without time zone".
+var typeAliases = map[string]string{
+	"int8":        "bigint",
+	"bigserial":   "bigint",
+	"serial8":     "bigint",
+	"int":         "integer",
+	"int4":        "integer",
+	"serial":      "integer",
+	"serial4":     "integer",
+	"int2":        "smallint",
+	"smallserial": "smallint",
+	"serial2":     "smallint",
+	"float":       "double precision",
+	"float8":      "double precision",
+	"float4":      "real",
+	"decimal":     "numeric",
+	"bool":        "boolean",
+	"varchar":     "character varying",
+	"char":        "character",
+	"bpchar":      "character",
+	"varbit":      "bit varying",
+	"timestamp":   "timestamp without time zone",
+	"timestamptz": "timestamp with time zone",
+	"time":        "time without time zone",
+	"timetz":      "time with time zone",
+}
+
+func (postgresDialect) NormalizeType(typ string) (name, modifiers string) {
+	return normalizeType(typ)
+}
+
+// normalizeType splits typ into its canonical name and its modifiers, e.g.
+// "VARCHAR(120)" becomes "character varying" and "120". Modifiers may sit
+// in the middle of the name, as in "timestamp(3) without time zone", and
+// array suffixes are kept on the name. float(p) is resolved to real or
+// double precision the way Postgres does it, and numeric(p) gets its
+// implicit scale of 0.
+func normalizeType(typ string) (name, modifiers string) {
+	name = strings.ToLower(strings.TrimSpace(typ))
+	array := ""
+	for strings.HasSuffix(name, "[]") {
+		array += "[]"
+		name = strings.TrimSpace(strings.TrimSuffix(name, "[]"))
+	}
+	if open := strings.Index(name, "("); open >= 0 {
+		if end := strings.Index(name[open:], ")"); end >= 0 {
+			modifiers = strings.Join(strings.Fields(name[open+1:open+end]), "")
+			name = name[:open] + " " + name[open+end+1:]
+		}
+	}
+	name = strings.Join(strings.Fields(name), " ")
+	if alias, ok := typeAliases[name]; ok {
+		name = alias
+	}
+
+	switch name {
+	case "double precision":
+		if p, err := strconv.Atoi(modifiers); err == nil && p <= 24 {
+			name = "real"
+		}
+		modifiers = ""
+	case "numeric":
+		if modifiers != "" && !strings.Contains(modifiers, ",") {
+			modifiers += ",0"
+		}
+	}
+	return name + array, modifiers
+}
+
+// canonicalTypeName returns the canonical name of typ without modifiers.
+func canonicalTypeName(typ string) string {
+	name, _ := normalizeType(typ)
+	return name
+}
+
+var serialTypes = map[string]string{
+	"smallint": "SMALLSERIAL",
+	"integer":  "SERIAL",
+	"bigint":   "BIGSERIAL",
+}
+
+func (postgresDialect) columnDefinition(c Column) string {
+	typ := c.Type
+	if serial, ok := serialTypes[canonicalTypeName(typ)]; ok && c.AutoIncrement {
+		typ = serial
+	}
+	def := fmt.Sprintf("%s %s", quoteIdent(c.Name), typ)
+	if c.NotNull {
+		def += " NOT NULL"
+	}
+	if c.Default != "" {
+		def += fmt.Sprintf(" DEFAULT %s", c.Default)
+	}
+	return def
+}
+
+// constraintDefinition renders a primary key, a unique constraint, or a
+// check constraint when c has no columns.
+func (postgresDialect) constraintDefinition(c Constraint) string {
+	if c.Primary {
+		return fmt.Sprintf("CONSTRAINT %s PRIMARY KEY (%s)", quoteIdent(c.Name), quoteIdents(c.Columns))
+	}
+	if len(c.Columns) == 0 {
+		return fmt.Sprintf("CONSTRAINT %s CHECK (%s)", quoteIdent(c.Name), c.Definition)
+	}
+	return fmt.Sprintf("CONSTRAINT %s UNIQUE (%s)", quoteIdent(c.Name), quoteIdents(c.Columns))
+}
+
+func (d postgresDialect) CreateTable(table string, columns []Column, primaryKey []string, constraints []Constraint) string {
+	definitions := make([]string, 0, len(columns)+len(constraints)+1)
+	for _, column := range columns {
+		definitions = append(definitions, d.columnDefinition(column))
+	}
+	if len(primaryKey) > 0 {
+		definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", quoteIdents(primaryKey)))
+	}
+	for _, constraint := range constraints {
+		definitions = append(definitions, d.constraintDefinition(constraint))
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n%s\n);", quoteIdent(table), strings.Join(definitions, ",\n"))
+}
+
+func (postgresDialect) DropTable(table string) string {
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s;", quoteIdent(table))
+}
+
+// ColumnComment removes the comment when comment is empty.
+func (postgresDialect) ColumnComment(table, column, comment string) string {
+	if comment == "" {
+		return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS NULL;", quoteIdent(table), quoteIdent(column))
+	}
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", quoteIdent(table), quoteIdent(column), quoteLiteral(comment))
+}
+
+func (d postgresDialect) AddColumn(table string, column Column) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", quoteIdent(table), d.columnDefinition(column))
+}
+
+func (postgresDialect) DropColumn(table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", quoteIdent(table), quoteIdent(column))
+}
+
+func (postgresDialect) AlterColumnType(table, column, typ string) string {
+	column = quoteIdent(column)
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s;", quoteIdent(table), column, typ, column, typ)
+}
+
+func (postgresDialect) SetNotNull(table, column string, notNull bool) string {
+	action := "DROP"
+	if notNull {
+		action = "SET"
+	}
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s NOT NULL;", quoteIdent(table), quoteIdent(column), action)
+}
+
+// SetDefault drops the default when value is empty.
+func (postgresDialect) SetDefault(table, column, value string) string {
+	if value == "" {
+		return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;", quoteIdent(table), quoteIdent(column))
+	}
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", quoteIdent(table), quoteIdent(column), value)
+}
+
+func (d postgresDialect) AddConstraint(table string, constraint Constraint) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;", quoteIdent(table), d.constraintDefinition(constraint))
+}
+
+func (postgresDialect) DropConstraint(table, name string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s;", quoteIdent(table), quoteIdent(name))
+}
+
+// CreateIndex quotes key columns but leaves expressions and the WHERE
+// clause as the model wrote them.
+func (postgresDialect) CreateIndex(table string, index Index) string {
+	def := "CREATE "
+	if index.Unique {
+		def += "UNIQUE "
+	}
+	def += "INDEX "
+	option := strings.TrimSpace(index.Option)
+	if isConcurrentIndex(index) {
+		def += "CONCURRENTLY "
+		option = ""
+	}
+	def += fmt.Sprintf("%s ON %s", quoteIdent(index.Name), quoteIdent(table))
+	if index.Method != "" && !strings.EqualFold(index.Method, "btree") {
+		def += " USING " + index.Method
+	}
+	keys := make([]string, len(index.Columns))
+	for i, key := range index.Columns {
+		keys[i] = quoteIndexKey(key)
+	}
+	def += fmt.Sprintf(" (%s)", strings.Join(keys, ", "))
+	if option != "" {
+		def += " " + option
+	}
+	if index.Where != "" {
+		def += " WHERE " + index.Where
+	}
+	return def + ";"
+}
+
+func (postgresDialect) DropIndex(table string, index Index) string {
+	if isConcurrentIndex(index) {
+		return fmt.Sprintf("DROP INDEX CONCURRENTLY IF EXISTS %s;", quoteIdent(index.Name))
+	}
+	return fmt.Sprintf("DROP INDEX IF EXISTS %s;", quoteIdent(index.Name))
+}
+
+func (postgresDialect) AddForeignKey(table string, fk ForeignKey) string {
+	def := fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+		quoteIdent(table), quoteIdent(fk.Name), quoteIdents(fk.Columns), quoteIdent(fk.RefTable), quoteIdents(fk.RefColumns))
+	if fk.OnUpdate != "" {
+		def += " ON UPDATE " + fk.OnUpdate
+	}
+	if fk.OnDelete != "" {
+		def += " ON DELETE " + fk.OnDelete
+	}
+	return def + ";"
+}
+
+func (d postgresDialect) DropForeignKey(table string, fk ForeignKey) string {
+	return d.DropConstraint(table, fk.Name)
+}
+
+// quoteIdent quotes a table, column or constraint name the way GORM's
+// QuoteTo does, so reserved words and mixed case survive. Schema-qualified
+// names are quoted part by part.
+func quoteIdent(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = `"` + strings.ReplaceAll(part, `"`, `""`) + `"`
+	}
+	return strings.Join(parts, ".")
+}
+
+func quoteIdents(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteIdent(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// identifier matches a bare column name, as opposed to an expression.
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// quoteIndexKey quotes an index key that names a column, keeping its
+// COLLATE and sort order. Expressions are returned unchanged.
+func quoteIndexKey(key string) string {
+	column := indexKeyExpression(key)
+	if !identifier.MatchString(column) {
+		return key
+	}
+	return quoteIdent(column) + key[len(column):]
+}
+
+// unquoteIdent returns the name inside a quoted identifier, which is how
+// Postgres reports reserved-word and mixed-case index keys.
+func unquoteIdent(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' && !strings.Contains(strings.ReplaceAll(s[1:len(s)-1], `""`, ""), `"`) {
+		return strings.ReplaceAll(s[1:len(s)-1], `""`, `"`)
+	}
+	return s
+}
+
+// quoteLiteral quotes s as an SQL string literal.
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
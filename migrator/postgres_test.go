@@ -0,0 +1,351 @@
+// File: migrator/postgres_test.go
+
+package migrator
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// newTestMigrator returns a Migrator that never connects to a database,
+// which is enough to parse models and render DDL.
+func newTestMigrator(t *testing.T) *Migrator {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=/nonexistent"}), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Migrator{db: db, dialect: postgresDialect{dialector: db.Dialector}, models: NewRegistry()}
+}
+
+func parseTestModel(t *testing.T, m *Migrator, model interface{}) *modelInfo {
+	t.Helper()
+	info, err := m.parseModel(model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
+
+// UUID has the name and layout of uuid.UUID, which is all knownType looks at.
+type UUID [16]byte
+
+// testJSON picks its own column type, like datatypes.JSON.
+type testJSON map[string]interface{}
+
+func (j testJSON) Value() (driver.Value, error) { return json.Marshal(j) }
+
+func (j *testJSON) Scan(value interface{}) error { return nil }
+
+func (testJSON) GormDBDataType(*gorm.DB, *schema.Field) string { return "jsonb" }
+
+// testTags is a slice that declares its own data type.
+type testTags []string
+
+func (testTags) GormDataType() string { return "json" }
+
+func (t testTags) Value() (driver.Value, error) { return json.Marshal(t) }
+
+func (t *testTags) Scan(value interface{}) error { return nil }
+
+// uuid is named like uuid.UUID but declares a text column.
+type uuid string
+
+func (uuid) GormDataType() string { return "char(36)" }
+
+type dataTypeModel struct {
+	ID          uint
+	Bool        bool
+	Int         int
+	Int8        int8
+	Int16       int16
+	Int32       int32
+	Int64       int64
+	Uint        uint
+	Uint8       uint8
+	Uint16      uint16
+	Uint32      uint32
+	Uint64      uint64
+	Float32     float32
+	Float64     float64
+	String      string
+	Bytes       []byte
+	Time        time.Time
+	UUID        UUID
+	Map         map[string]string `gorm:"serializer:json"`
+	Raw         json.RawMessage
+	Doc         testJSON
+	Tags        testTags
+	CharUUID    uuid
+	StringPtr   *string
+	Int64Ptr    *int64
+	TimePtr     *time.Time
+	NullString  sql.NullString
+	NullInt64   sql.NullInt64
+	NullInt32   sql.NullInt32
+	NullInt16   sql.NullInt16
+	NullByte    sql.NullByte
+	NullFloat64 sql.NullFloat64
+	NullBool    sql.NullBool
+	NullTime    sql.NullTime
+	NullGeneric sql.Null[string]
+	DeletedAt   gorm.DeletedAt
+	Sized       string    `gorm:"size:64"`
+	SizedInt    int       `gorm:"size:16"`
+	Money       float64   `gorm:"precision:10;scale:2"`
+	Ratio       float64   `gorm:"precision:5"`
+	Stamp       time.Time `gorm:"precision:3"`
+	TypeUUID    string    `gorm:"type:uuid"`
+	TypeVarchar string    `gorm:"type:varchar;size:20"`
+	TypeNumeric float64   `gorm:"type:numeric;precision:12;scale:2"`
+	TypeArray   []string  `gorm:"type:text[]"`
+	TypeFull    string    `gorm:"type:varchar(8);size:20"`
+}
+
+func TestPostgresDataType(t *testing.T) {
+	m := newTestMigrator(t)
+	info := parseTestModel(t, m, &dataTypeModel{})
+
+	tests := []struct {
+		field string
+		want  string
+	}{
+		// One case per reflect.Kind GORM maps to a column.
+		{"ID", "bigserial"},
+		{"Bool", "boolean"},
+		{"Int", "bigint"},
+		{"Int8", "smallint"},
+		{"Int16", "smallint"},
+		{"Int32", "integer"},
+		{"Int64", "bigint"},
+		{"Uint", "bigint"},
+		{"Uint8", "smallint"},
+		{"Uint16", "integer"},
+		{"Uint32", "bigint"},
+		{"Uint64", "bigint"},
+		{"Float32", "decimal"},
+		{"Float64", "decimal"},
+		{"String", "text"},
+		{"Bytes", "bytea"},
+		{"Time", "timestamptz"},
+		{"UUID", "UUID"},
+		{"Map", "text"},
+		{"Raw", "JSONB"},
+		{"Doc", "jsonb"},
+		{"Tags", "JSONB"},
+		{"CharUUID", "char(36)"},
+
+		// Pointers and nullable wrappers map to the type they hold.
+		{"StringPtr", "text"},
+		{"Int64Ptr", "bigint"},
+		{"TimePtr", "timestamptz"},
+		{"NullString", "text"},
+		{"NullInt64", "bigint"},
+		{"NullInt32", "integer"},
+		{"NullInt16", "smallint"},
+		{"NullByte", "smallint"},
+		{"NullFloat64", "decimal"},
+		{"NullBool", "boolean"},
+		{"NullTime", "timestamptz"},
+		{"NullGeneric", "text"},
+		{"DeletedAt", "timestamptz"},
+
+		// size, precision and scale.
+		{"Sized", "varchar(64)"},
+		{"SizedInt", "smallint"},
+		{"Money", "numeric(10, 2)"},
+		{"Ratio", "numeric(5)"},
+		{"Stamp", "timestamptz(3)"},
+
+		// Explicit types.
+		{"TypeUUID", "uuid"},
+		{"TypeVarchar", "varchar(20)"},
+		{"TypeNumeric", "numeric(12,2)"},
+		{"TypeArray", "text[]"},
+		{"TypeFull", "varchar(8)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field := info.Schema.LookUpField(tt.field)
+			if field == nil {
+				t.Fatalf("field %s not found", tt.field)
+			}
+			if got := m.columnType(field); got != tt.want {
+				t.Errorf("columnType(%s) = %q, want %q", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameColumnType(t *testing.T) {
+	d := postgresDialect{}
+	tests := []struct {
+		model, live string
+		want        bool
+	}{
+		{"timestamptz", "timestamp with time zone", true},
+		{"timestamptz(3)", "timestamp(3) with time zone", true},
+		{"timestamptz", "timestamp without time zone", false},
+		{"decimal", "numeric", true},
+		{"decimal", "numeric(10,2)", true},
+		{"numeric(10, 2)", "numeric(10,2)", true},
+		{"numeric(5)", "numeric(5,0)", true},
+		{"numeric(10, 2)", "numeric(12,2)", false},
+		{"varchar(64)", "character varying(64)", true},
+		{"varchar(64)", "character varying(32)", false},
+		{"text", "character varying(64)", false},
+		{"bigserial", "bigint", true},
+		{"UUID", "uuid", true},
+		{"text[]", "text[]", true},
+		{"JSONB", "json", false},
+	}
+	for _, tt := range tests {
+		got := sameColumnType(d, Column{Type: tt.model}, Column{Type: tt.live})
+		if got != tt.want {
+			t.Errorf("sameColumnType(%q, %q) = %v, want %v", tt.model, tt.live, got, tt.want)
+		}
+	}
+}
+
+type createTableModel struct {
+	ID        uint
+	Name      string  `gorm:"not null;default:'anonymous';comment:display name"`
+	Email     string  `gorm:"size:120;unique"`
+	Age       *int    `gorm:"check:age >= 0"`
+	Balance   float64 `gorm:"precision:12;scale:2;check:balance_positive,balance > 0"`
+	CreatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+func TestPostgresCreateTable(t *testing.T) {
+	m := newTestMigrator(t)
+	info := parseTestModel(t, m, &createTableModel{})
+
+	want := `CREATE TABLE "create_table_models" (
+"id" BIGSERIAL,
+"name" text NOT NULL DEFAULT 'anonymous',
+"email" varchar(120),
+"age" bigint,
+"balance" numeric(12, 2),
+"created_at" timestamptz,
+"deleted_at" timestamptz,
+PRIMARY KEY ("id"),
+CONSTRAINT "uni_create_table_models_email" UNIQUE ("email"),
+CONSTRAINT "balance_positive" CHECK (balance > 0),
+CONSTRAINT "chk_create_table_models_age" CHECK (age >= 0)
+);
+COMMENT ON COLUMN "create_table_models"."name" IS 'display name';
+`
+	if got := m.generateCreateTableSQL(info); got != want {
+		t.Errorf("generateCreateTableSQL() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+type addColumnModel struct {
+	ID       uint
+	Nick     *string
+	Score    int    `gorm:"not null"`
+	Status   string `gorm:"not null;default:'active'"`
+	Note     string `gorm:"comment:it's free text"`
+	Code     string `gorm:"size:8;unique"`
+	Verified bool   `gorm:"not null"`
+	SeenAt   time.Time
+}
+
+func TestPostgresAddColumn(t *testing.T) {
+	m := newTestMigrator(t)
+	info := parseTestModel(t, m, &addColumnModel{})
+	live := &Table{
+		Name:       "add_column_models",
+		Columns:    []*Column{{Name: "id", Type: "bigint", NotNull: true}},
+		PrimaryKey: &Constraint{Name: "add_column_models_pkey", Columns: []string{"id"}, Primary: true},
+	}
+
+	changes := m.compareModelToTable(info, live)
+	changes = append(changes, m.compareConstraints(info, live)...)
+
+	wantUp := `ALTER TABLE "add_column_models" ADD COLUMN "nick" text;
+ALTER TABLE "add_column_models" ADD COLUMN "score" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "add_column_models" ALTER COLUMN "score" DROP DEFAULT;
+ALTER TABLE "add_column_models" ADD COLUMN "status" text NOT NULL DEFAULT 'active';
+ALTER TABLE "add_column_models" ADD COLUMN "note" text;
+COMMENT ON COLUMN "add_column_models"."note" IS 'it''s free text';
+ALTER TABLE "add_column_models" ADD COLUMN "code" varchar(8);
+ALTER TABLE "add_column_models" ADD COLUMN "verified" boolean NOT NULL DEFAULT false;
+ALTER TABLE "add_column_models" ALTER COLUMN "verified" DROP DEFAULT;
+ALTER TABLE "add_column_models" ADD COLUMN "seen_at" timestamptz;
+ALTER TABLE "add_column_models" ADD CONSTRAINT "uni_add_column_models_code" UNIQUE ("code");`
+	if got := renderUp(m.dialect, changes); got != wantUp {
+		t.Errorf("up =\n%s\nwant\n%s", got, wantUp)
+	}
+
+	wantDown := `ALTER TABLE "add_column_models" DROP CONSTRAINT IF EXISTS "uni_add_column_models_code";
+ALTER TABLE "add_column_models" DROP COLUMN "seen_at";
+ALTER TABLE "add_column_models" DROP COLUMN "verified";
+ALTER TABLE "add_column_models" DROP COLUMN "code";
+ALTER TABLE "add_column_models" DROP COLUMN "note";
+ALTER TABLE "add_column_models" DROP COLUMN "status";
+ALTER TABLE "add_column_models" DROP COLUMN "score";
+ALTER TABLE "add_column_models" DROP COLUMN "nick";`
+	if got := renderDown(m.dialect, changes); got != wantDown {
+		t.Errorf("down =\n%s\nwant\n%s", got, wantDown)
+	}
+}
+
+type quotedModel struct {
+	ID     uint
+	Order  int    `gorm:"index"`
+	User   string `gorm:"index:idx_quoted_models_user,expression:lower(\"user\")"`
+	UserID uint   `gorm:"column:UserID;unique"`
+}
+
+// TestPostgresQuotedIdentifiers covers a reserved word and a mixed-case
+// column name, which only work quoted.
+func TestPostgresQuotedIdentifiers(t *testing.T) {
+	m := newTestMigrator(t)
+	info := parseTestModel(t, m, &quotedModel{})
+
+	want := `CREATE TABLE "quoted_models" (
+"id" BIGSERIAL,
+"order" bigint,
+"user" text,
+"UserID" bigint,
+PRIMARY KEY ("id"),
+CONSTRAINT "uni_quoted_models_user_id" UNIQUE ("UserID")
+);
+CREATE INDEX "idx_quoted_models_order" ON "quoted_models" ("order");
+CREATE INDEX "idx_quoted_models_user" ON "quoted_models" (lower("user"));`
+	if got := renderUp(m.dialect, append([]SchemaChange{CreateTable{Table: info.Table, SQL: m.generateCreateTableSQL(info)}}, m.createIndexChanges(info)...)); got != want {
+		t.Errorf("up =\n%s\nwant\n%s", got, want)
+	}
+
+	// The live table reports the names unfolded, so nothing changes.
+	live := &Table{
+		Name: "quoted_models",
+		Columns: []*Column{
+			{Name: "id", Type: "bigint", NotNull: true},
+			{Name: "order", Type: "bigint"},
+			{Name: "user", Type: "text"},
+			{Name: "UserID", Type: "bigint"},
+		},
+		PrimaryKey: &Constraint{Name: "quoted_models_pkey", Columns: []string{"id"}, Primary: true},
+		Uniques:    []Constraint{{Name: "uni_quoted_models_user_id", Columns: []string{"UserID"}}},
+		Indexes: []Index{
+			{Name: "idx_quoted_models_order", Columns: []string{`"order"`}},
+			{Name: "idx_quoted_models_user", Columns: []string{`lower("user")`}},
+		},
+	}
+	changes := m.compareModelToTable(info, live)
+	changes = append(changes, m.compareConstraints(info, live)...)
+	changes = append(changes, m.compareIndexes(info, live)...)
+	if len(changes) != 0 {
+		t.Errorf("changes against the live table =\n%s", renderUp(m.dialect, changes))
+	}
+}
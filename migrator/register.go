@@ -0,0 +1,73 @@
+// File: migrator/register.go
+
+package migrator
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Registry is a thread-safe, ordered set of models. A model is identified
+// by its type, so registering &User{} and User{} twice keeps only the first
+// registration. Models are returned in the order they were first
+// registered, which is the order their migrations are generated in.
+type Registry struct {
+	mu     sync.Mutex
+	models []interface{}
+	types  map[reflect.Type]bool
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{types: make(map[reflect.Type]bool)}
+}
+
+// Register adds models that are not registered yet, in order.
+func (r *Registry) Register(models ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, model := range models {
+		if model == nil {
+			continue
+		}
+		t := reflect.TypeOf(model)
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if r.types[t] {
+			continue
+		}
+		r.types[t] = true
+		r.models = append(r.models, model)
+	}
+}
+
+// Models returns a copy of the registered models in registration order.
+func (r *Registry) Models() []interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]interface{}(nil), r.models...)
+}
+
+// defaultRegistry holds the models registered with RegisterModel and
+// RegisterModels. Every Migrator picks them up.
+var defaultRegistry = NewRegistry()
+
+// RegisterModel allows users to register their models, typically from an
+// init function of the package that defines them.
+func RegisterModel(model interface{}) {
+	defaultRegistry.Register(model)
+}
+
+// RegisterModels registers several models at once, in order.
+func RegisterModels(models ...interface{}) {
+	defaultRegistry.Register(models...)
+}
+
+// RegisteredModels returns the globally registered models in registration
+// order.
+func RegisteredModels() []interface{} {
+	return defaultRegistry.Models()
+}
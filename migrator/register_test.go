@@ -0,0 +1,69 @@
+// File: migrator/register_test.go
+
+package migrator
+
+import (
+	"reflect"
+	"testing"
+)
+
+type registryUser struct{ ID uint }
+
+type registryOrder struct{ ID uint }
+
+func TestRegistryRegister(t *testing.T) {
+	user, order := &registryUser{}, &registryOrder{}
+
+	tests := []struct {
+		name   string
+		models []interface{}
+		want   []interface{}
+	}{
+		{
+			name:   "registration order",
+			models: []interface{}{order, user},
+			want:   []interface{}{order, user},
+		},
+		{
+			name:   "pointer then value",
+			models: []interface{}{user, registryUser{}},
+			want:   []interface{}{user},
+		},
+		{
+			name:   "value then pointer",
+			models: []interface{}{registryUser{}, user},
+			want:   []interface{}{registryUser{}},
+		},
+		{
+			name:   "same pointer twice",
+			models: []interface{}{user, order, user},
+			want:   []interface{}{user, order},
+		},
+		{
+			name:   "nil is skipped",
+			models: []interface{}{nil, user, nil},
+			want:   []interface{}{user},
+		},
+		{
+			name: "nothing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			r.Register(tt.models...)
+			if got := r.Models(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Models() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryModelsIsACopy(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&registryUser{})
+	r.Models()[0] = &registryOrder{}
+	if _, ok := r.Models()[0].(*registryUser); !ok {
+		t.Errorf("changing the result of Models() changed the registry")
+	}
+}
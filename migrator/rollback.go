@@ -0,0 +1,107 @@
+// File: migrator/rollback.go
+
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Rollback reverts the last steps applied migrations, newest first.
+func (m *Migrator) Rollback(ctx context.Context, steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be greater than zero, got %d", steps)
+	}
+
+	applied, err := m.loadAppliedForRollback(ctx)
+	if err != nil {
+		return err
+	}
+	if steps > len(applied) {
+		steps = len(applied)
+	}
+	return m.rollbackMigrations(ctx, applied[len(applied)-steps:])
+}
+
+// RollbackTo reverts every applied migration newer than version. Version
+// itself stays applied; pass "0" to revert everything.
+func (m *Migrator) RollbackTo(ctx context.Context, version string) error {
+	applied, err := m.loadAppliedForRollback(ctx)
+	if err != nil {
+		return err
+	}
+
+	cut := -1
+	if version != "0" {
+		for i, a := range applied {
+			if a.Version == version {
+				cut = i
+				break
+			}
+		}
+		if cut == -1 {
+			return fmt.Errorf("migration %s has not been applied", version)
+		}
+	}
+	return m.rollbackMigrations(ctx, applied[cut+1:])
+}
+
+func (m *Migrator) loadAppliedForRollback(ctx context.Context) ([]appliedMigration, error) {
+	if err := m.ensureHistoryTable(ctx); err != nil {
+		return nil, err
+	}
+	return m.appliedMigrations(ctx)
+}
+
+// rollbackMigrations runs the down file for each of the given migrations in
+// reverse order. applied must be sorted oldest first.
+func (m *Migrator) rollbackMigrations(ctx context.Context, applied []appliedMigration) error {
+	if len(applied) == 0 {
+		fmt.Println("No migrations to roll back")
+		return nil
+	}
+
+	migrations, err := readMigrations(m.config.OutputDir)
+	if err != nil {
+		return err
+	}
+	byVersion := make(map[string]Migration, len(migrations))
+	for _, mig := range migrations {
+		byVersion[mig.Version] = mig
+	}
+
+	for i := len(applied) - 1; i >= 0; i-- {
+		mig, ok := byVersion[applied[i].Version]
+		if !ok || mig.Down == "" {
+			return fmt.Errorf("no down migration found for %s_%s", applied[i].Version, applied[i].Name)
+		}
+		if err := m.rollbackMigration(ctx, mig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) rollbackMigration(ctx context.Context, mig Migration) error {
+	if m.config.Debug {
+		fmt.Printf("Rolling back %s:\n%s\n", mig.filename("down"), mig.Down)
+	}
+
+	start := time.Now()
+	err := m.runMigration(ctx, mig.Version, mig.Down, func(exec execer) error {
+		if err := execScript(ctx, exec, mig.Down); err != nil {
+			return fmt.Errorf("failed to roll back migration %s_%s: %v", mig.Version, mig.Name, err)
+		}
+		if _, err := exec.ExecContext(ctx, "DELETE FROM "+historyTable+" WHERE version = $1", mig.Version); err != nil {
+			return fmt.Errorf("failed to remove migration %s from history: %v", mig.Version, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Rolled back migration: %s_%s (%s)\n", mig.Version, mig.Name, time.Since(start).Round(time.Millisecond))
+	return nil
+}
@@ -0,0 +1,128 @@
+// File: migrator/schema.go
+
+package migrator
+
+import "strings"
+
+// Schema is an in-memory snapshot of the tables in a database schema.
+type Schema struct {
+	Tables map[string]*Table
+}
+
+// Table returns the table with the given name, or nil if it does not exist.
+func (s *Schema) Table(name string) *Table {
+	if s == nil {
+		return nil
+	}
+	return s.Tables[name]
+}
+
+type Table struct {
+	Name        string
+	Comment     string
+	Columns     []*Column
+	PrimaryKey  *Constraint
+	Uniques     []Constraint
+	ForeignKeys []ForeignKey
+	Indexes     []Index
+	Checks      []Constraint
+}
+
+// Column returns the column with the given name, or nil if it does not exist.
+func (t *Table) Column(name string) *Column {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Index returns the index with the given name, or nil if it does not exist.
+func (t *Table) Index(name string) *Index {
+	for i := range t.Indexes {
+		if t.Indexes[i].Name == name {
+			return &t.Indexes[i]
+		}
+	}
+	return nil
+}
+
+// hasUnique reports whether a unique constraint covers exactly columns.
+func (t *Table) hasUnique(columns []string) bool {
+	for _, constraint := range t.Uniques {
+		if strings.Join(constraint.Columns, ",") == strings.Join(columns, ",") {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns the check constraint with the given name, or nil if it does
+// not exist.
+func (t *Table) Check(name string) *Constraint {
+	for i := range t.Checks {
+		if t.Checks[i].Name == name {
+			return &t.Checks[i]
+		}
+	}
+	return nil
+}
+
+// ForeignKey returns the foreign key with the given name, or nil if it does
+// not exist.
+func (t *Table) ForeignKey(name string) *ForeignKey {
+	for i := range t.ForeignKeys {
+		if t.ForeignKeys[i].Name == name {
+			return &t.ForeignKeys[i]
+		}
+	}
+	return nil
+}
+
+// Column describes a table column as it appears in DDL. The type keeps its
+// modifiers, e.g. varchar(64). AutoIncrement columns are rendered with the
+// matching serial type.
+type Column struct {
+	Name          string
+	Type          string
+	NotNull       bool
+	Default       string
+	AutoIncrement bool
+	Comment       string
+}
+
+// Constraint describes a primary key, unique or check constraint. Columns
+// is empty for check constraints, whose Definition holds the expression.
+type Constraint struct {
+	Name       string
+	Columns    []string
+	Definition string
+	Primary    bool
+}
+
+// Index describes a table index. Columns holds column names or
+// expressions, optionally followed by COLLATE and ASC/DESC. Option is
+// CONCURRENTLY or a storage clause such as WITH (fillfactor = 70). Primary
+// and Constraint are set for introspected indexes that back a primary key
+// or unique constraint.
+type Index struct {
+	Name       string
+	Columns    []string
+	Unique     bool
+	Method     string
+	Where      string
+	Option     string
+	Primary    bool
+	Constraint bool
+}
+
+// ForeignKey describes a foreign-key constraint.
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnUpdate   string
+	OnDelete   string
+}
@@ -0,0 +1,248 @@
+// File: migrator/schema_changes.go
+
+package migrator
+
+import "strings"
+
+// SchemaChange is a single DDL change that knows how to render itself and
+// its inverse, so every generated up migration has a matching down.
+type SchemaChange interface {
+	UpSQL(d Dialect) string
+	DownSQL(d Dialect) string
+}
+
+// CreateTable creates a table from a complete CREATE TABLE statement.
+type CreateTable struct {
+	Table string
+	SQL   string
+}
+
+func (c CreateTable) UpSQL(d Dialect) string {
+	return c.SQL
+}
+
+func (c CreateTable) DownSQL(d Dialect) string {
+	return d.DropTable(c.Table)
+}
+
+// AddColumn adds a column. A NOT NULL column without a default cannot be
+// added to a table that has rows, so Backfill, when set, is used as a
+// temporary default that fills the existing rows.
+type AddColumn struct {
+	Table    string
+	Column   Column
+	Backfill string
+}
+
+func (c AddColumn) UpSQL(d Dialect) string {
+	var sql string
+	if c.Backfill == "" || c.Column.Default != "" {
+		sql = d.AddColumn(c.Table, c.Column)
+	} else {
+		column := c.Column
+		column.Default = c.Backfill
+		sql = d.AddColumn(c.Table, column) + "\n" + d.SetDefault(c.Table, column.Name, "")
+	}
+	if c.Column.Comment != "" {
+		sql += "\n" + d.ColumnComment(c.Table, c.Column.Name, c.Column.Comment)
+	}
+	return sql
+}
+
+func (c AddColumn) DownSQL(d Dialect) string {
+	return d.DropColumn(c.Table, c.Column.Name)
+}
+
+// DropColumn keeps the full column so the down migration can recreate it.
+type DropColumn struct {
+	Table  string
+	Column Column
+}
+
+func (c DropColumn) UpSQL(d Dialect) string {
+	return AddColumn{Table: c.Table, Column: c.Column}.DownSQL(d)
+}
+
+func (c DropColumn) DownSQL(d Dialect) string {
+	return AddColumn{Table: c.Table, Column: c.Column}.UpSQL(d)
+}
+
+type AlterColumnType struct {
+	Table  string
+	Column string
+	From   string
+	To     string
+}
+
+func (c AlterColumnType) UpSQL(d Dialect) string {
+	return d.AlterColumnType(c.Table, c.Column, c.To)
+}
+
+func (c AlterColumnType) DownSQL(d Dialect) string {
+	return d.AlterColumnType(c.Table, c.Column, c.From)
+}
+
+// SetNotNull adds the NOT NULL constraint to a column, or drops it when
+// NotNull is false. Existing NULLs are left alone: replacing them could not
+// be undone by the down migration.
+type SetNotNull struct {
+	Table   string
+	Column  string
+	NotNull bool
+}
+
+func (c SetNotNull) UpSQL(d Dialect) string {
+	return d.SetNotNull(c.Table, c.Column, c.NotNull)
+}
+
+func (c SetNotNull) DownSQL(d Dialect) string {
+	return d.SetNotNull(c.Table, c.Column, !c.NotNull)
+}
+
+// SetDefault changes a column default. An empty Default or Previous means
+// the column has no default.
+type SetDefault struct {
+	Table    string
+	Column   string
+	Default  string
+	Previous string
+}
+
+func (c SetDefault) UpSQL(d Dialect) string {
+	return d.SetDefault(c.Table, c.Column, c.Default)
+}
+
+func (c SetDefault) DownSQL(d Dialect) string {
+	return d.SetDefault(c.Table, c.Column, c.Previous)
+}
+
+// SetComment changes a column comment. An empty Comment or Previous means
+// no comment.
+type SetComment struct {
+	Table    string
+	Column   string
+	Comment  string
+	Previous string
+}
+
+func (c SetComment) UpSQL(d Dialect) string {
+	return d.ColumnComment(c.Table, c.Column, c.Comment)
+}
+
+func (c SetComment) DownSQL(d Dialect) string {
+	return d.ColumnComment(c.Table, c.Column, c.Previous)
+}
+
+// AddConstraint adds a unique or check constraint.
+type AddConstraint struct {
+	Table      string
+	Constraint Constraint
+}
+
+func (c AddConstraint) UpSQL(d Dialect) string {
+	return d.AddConstraint(c.Table, c.Constraint)
+}
+
+func (c AddConstraint) DownSQL(d Dialect) string {
+	return d.DropConstraint(c.Table, c.Constraint.Name)
+}
+
+// DropConstraint keeps the full constraint so the down migration can
+// recreate it.
+type DropConstraint struct {
+	Table      string
+	Constraint Constraint
+}
+
+func (c DropConstraint) UpSQL(d Dialect) string {
+	return AddConstraint(c).DownSQL(d)
+}
+
+func (c DropConstraint) DownSQL(d Dialect) string {
+	return AddConstraint(c).UpSQL(d)
+}
+
+type AddIndex struct {
+	Table string
+	Index Index
+}
+
+func (c AddIndex) UpSQL(d Dialect) string {
+	return d.CreateIndex(c.Table, c.Index)
+}
+
+func (c AddIndex) DownSQL(d Dialect) string {
+	return d.DropIndex(c.Table, c.Index)
+}
+
+// DropIndex keeps the full index so the down migration can recreate it.
+type DropIndex struct {
+	Table string
+	Index Index
+}
+
+func (c DropIndex) UpSQL(d Dialect) string {
+	return AddIndex(c).DownSQL(d)
+}
+
+func (c DropIndex) DownSQL(d Dialect) string {
+	return AddIndex(c).UpSQL(d)
+}
+
+type AddForeignKey struct {
+	Table      string
+	ForeignKey ForeignKey
+}
+
+func (c AddForeignKey) UpSQL(d Dialect) string {
+	return d.AddForeignKey(c.Table, c.ForeignKey)
+}
+
+func (c AddForeignKey) DownSQL(d Dialect) string {
+	return d.DropForeignKey(c.Table, c.ForeignKey)
+}
+
+// DropForeignKey keeps the full constraint so the down migration can
+// recreate it.
+type DropForeignKey struct {
+	Table      string
+	ForeignKey ForeignKey
+}
+
+func (c DropForeignKey) UpSQL(d Dialect) string {
+	return AddForeignKey(c).DownSQL(d)
+}
+
+func (c DropForeignKey) DownSQL(d Dialect) string {
+	return AddForeignKey(c).UpSQL(d)
+}
+
+// isConcurrentChange reports whether c builds or drops an index
+// CONCURRENTLY.
+func isConcurrentChange(c SchemaChange) bool {
+	switch c := c.(type) {
+	case AddIndex:
+		return isConcurrentIndex(c.Index)
+	case DropIndex:
+		return isConcurrentIndex(c.Index)
+	}
+	return false
+}
+
+// renderUp returns the forward SQL of changes in order.
+func renderUp(d Dialect, changes []SchemaChange) string {
+	statements := make([]string, 0, len(changes))
+	for _, c := range changes {
+		statements = append(statements, c.UpSQL(d))
+	}
+	return strings.Join(statements, "\n")
+}
+
+// renderDown returns the inverse SQL of changes in reverse order.
+func renderDown(d Dialect, changes []SchemaChange) string {
+	statements := make([]string, 0, len(changes))
+	for i := len(changes) - 1; i >= 0; i-- {
+		statements = append(statements, changes[i].DownSQL(d))
+	}
+	return strings.Join(statements, "\n")
+}
@@ -0,0 +1,76 @@
+// File: migrator/sql_helpers.go
+
+package migrator
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+func (m *Migrator) buildColumn(field *schema.Field) Column {
+	// Default column definition
+	column := Column{
+		Name:          field.DBName,
+		Type:          m.columnType(field),
+		AutoIncrement: field.AutoIncrement && field.PrimaryKey,
+	}
+
+	// Like GORM, only the tag makes a column NOT NULL; primary keys are
+	// implicitly NOT NULL.
+	column.NotNull = field.NotNull
+	column.Default = m.columnDefault(field)
+	column.Comment = field.Comment
+	return column
+}
+
+// columnDefault renders the field's default the way GORM's migrator does:
+// values GORM parsed for the field's type are written as literals, and
+// anything else, such as now(), is used as written.
+func (m *Migrator) columnDefault(field *schema.Field) string {
+	if !field.HasDefaultValue || field.DefaultValue == "(-)" {
+		return ""
+	}
+	if field.DefaultValueInterface != nil {
+		stmt := &gorm.Statement{Vars: []interface{}{field.DefaultValueInterface}}
+		m.db.Dialector.BindVarTo(stmt, stmt, field.DefaultValueInterface)
+		return m.db.Dialector.Explain(stmt.SQL.String(), field.DefaultValueInterface)
+	}
+	return field.DefaultValue
+}
+
+func (m *Migrator) generateCreateTableSQL(info *modelInfo) string {
+	var columns []Column
+	var primaryKeys []string
+	var comments []string
+
+	for _, field := range info.columnFields() {
+		column := m.buildColumn(field)
+
+		// Handle primary key, including the implicit `ID` primary key
+		if field.PrimaryKey {
+			primaryKeys = append(primaryKeys, column.Name)
+		}
+
+		// Handle comments if present
+		if column.Comment != "" {
+			comments = append(comments, m.dialect.ColumnComment(info.Table, column.Name, column.Comment))
+		}
+
+		columns = append(columns, column)
+	}
+
+	if len(columns) == 0 {
+		return ""
+	}
+
+	sql := m.dialect.CreateTable(info.Table, columns, primaryKeys, m.modelConstraints(info))
+
+	// Add comments
+	if len(comments) > 0 {
+		sql += "\n" + strings.Join(comments, "\n") + "\n"
+	}
+
+	return sql
+}
@@ -0,0 +1,102 @@
+// File: migrator/status.go
+
+package migrator
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+type MigrationState string
+
+const (
+	StateApplied  MigrationState = "applied"
+	StatePending  MigrationState = "pending"
+	StateMissing  MigrationState = "missing"
+	StateModified MigrationState = "modified"
+)
+
+// MigrationStatus describes a single migration in a StatusReport.
+type MigrationStatus struct {
+	Version   string         `json:"version"`
+	Name      string         `json:"name"`
+	State     MigrationState `json:"state"`
+	AppliedAt *time.Time     `json:"applied_at,omitempty"`
+}
+
+// StatusReport compares the migration files on disk with the history table.
+type StatusReport struct {
+	Migrations []MigrationStatus `json:"migrations"`
+}
+
+// Count returns the number of migrations in the given state.
+func (r *StatusReport) Count(state MigrationState) int {
+	n := 0
+	for _, s := range r.Migrations {
+		if s.State == state {
+			n++
+		}
+	}
+	return n
+}
+
+// Status reports which migrations in the output directory are applied or
+// pending, which applied migrations are missing on disk, and which were
+// edited after being applied. It only reads: while the history table does
+// not exist yet, every migration is reported as pending.
+func (m *Migrator) Status(ctx context.Context) (*StatusReport, error) {
+	migrations, err := readMigrations(m.config.OutputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := m.historyTableExists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var applied []appliedMigration
+	if exists {
+		applied, err = m.appliedMigrations(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	history := make(map[string]appliedMigration, len(applied))
+	for _, a := range applied {
+		history[a.Version] = a
+	}
+
+	report := &StatusReport{}
+	for _, mig := range migrations {
+		status := MigrationStatus{Version: mig.Version, Name: mig.Name, State: StatePending}
+
+		if a, ok := history[mig.Version]; ok {
+			delete(history, mig.Version)
+			appliedAt := a.AppliedAt
+			status.AppliedAt = &appliedAt
+			status.State = StateApplied
+
+			if checksum([]byte(mig.Up)) != a.Checksum {
+				status.State = StateModified
+			}
+		}
+
+		report.Migrations = append(report.Migrations, status)
+	}
+
+	for _, a := range history {
+		appliedAt := a.AppliedAt
+		report.Migrations = append(report.Migrations, MigrationStatus{
+			Version:   a.Version,
+			Name:      a.Name,
+			State:     StateMissing,
+			AppliedAt: &appliedAt,
+		})
+	}
+
+	sort.Slice(report.Migrations, func(i, j int) bool {
+		return compareVersions(report.Migrations[i].Version, report.Migrations[j].Version) < 0
+	})
+	return report, nil
+}
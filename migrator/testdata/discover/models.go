@@ -0,0 +1,65 @@
+// File: migrator/testdata/discover/models.go
+
+// Package discover holds the models DiscoverModels is tested against.
+package discover
+
+import "gorm.io/gorm"
+
+// Base is a set of columns other models embed.
+type Base struct {
+	CreatedBy string `gorm:"size:64"`
+}
+
+type User struct {
+	gorm.Model
+	Base
+	Name string
+}
+
+// Admin embeds User, which has a primary key and is a table of its own.
+type Admin struct {
+	User
+	Level int
+}
+
+// Address is only used with `gorm:"embedded"`.
+type Address struct {
+	Street string `gorm:"size:100"`
+}
+
+type Shop struct {
+	Code string  `gorm:"primaryKey"`
+	Addr Address `gorm:"embedded;embeddedPrefix:addr_"`
+}
+
+// Keyed is embedded, but brings its own primary key.
+type Keyed struct {
+	Key string `gorm:"primaryKey"`
+}
+
+type Tenant struct {
+	Keyed
+	Name string `gorm:"size:10"`
+}
+
+// Hidden is embedded, and its ID is not a column.
+type Hidden struct {
+	ID   uint   `gorm:"-"`
+	Note string `gorm:"size:5"`
+}
+
+type Note struct {
+	Hidden
+	ID uint
+}
+
+// Plain has no gorm tags and is not a model.
+type Plain struct {
+	Name string
+}
+
+// unexported would be a model, but cannot be registered from outside the
+// package.
+type unexported struct {
+	ID uint `gorm:"primaryKey"`
+}
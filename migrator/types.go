@@ -0,0 +1,79 @@
+// File: migrator/types.go
+
+package migrator
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// TypeMapper returns the column type for a field, or "" to leave the field
+// to the built-in mapping. Mappers registered with RegisterTypeMapper are
+// consulted first, in registration order.
+type TypeMapper func(field *schema.Field) string
+
+// RegisterTypeMapper adds a mapper for types the migrator does not know,
+// e.g. types from a third-party package.
+func (m *Migrator) RegisterTypeMapper(mapper TypeMapper) {
+	m.typeMappers = append(m.typeMappers, mapper)
+}
+
+// gormDBDataTyper is implemented by types that pick their own column type
+// per dialect, like datatypes.JSON. GORM gives it priority over the `type`
+// tag, and so do we.
+type gormDBDataTyper interface {
+	GormDBDataType(*gorm.DB, *schema.Field) string
+}
+
+// columnType maps a field to its column type. Registered TypeMappers come
+// first, then GormDBDataType, which GORM itself gives priority over the
+// `type` tag, and finally the dialect. GORM already unwraps pointers and
+// nullable wrappers such as sql.NullString, sql.Null[T] and gorm.DeletedAt
+// to the type they hold.
+func (m *Migrator) columnType(field *schema.Field) string {
+	for _, mapper := range m.typeMappers {
+		if typ := mapper(field); typ != "" {
+			return typ
+		}
+	}
+	if typer, ok := reflect.New(field.IndirectFieldType).Interface().(gormDBDataTyper); ok {
+		if typ := typer.GormDBDataType(m.db, field); typ != "" {
+			return typ
+		}
+	}
+	return m.dialect.DataType(field)
+}
+
+// sameColumnType reports whether a live column already has the model
+// column's type. Modifiers such as a varchar length or a numeric precision
+// and scale are only compared when the model specifies them.
+func sameColumnType(d Dialect, model, live Column) bool {
+	name, modifiers := d.NormalizeType(model.Type)
+	liveName, liveModifiers := d.NormalizeType(live.Type)
+	if name != liveName {
+		return false
+	}
+	return modifiers == "" || modifiers == liveModifiers
+}
+
+// backfillValue returns the zero value GORM writes for field, used to fill
+// existing rows when a NOT NULL column without a default is added. It
+// returns "" when the column is nullable or has a default.
+func backfillValue(field *schema.Field, column Column) string {
+	if !column.NotNull || column.Default != "" || field.PrimaryKey {
+		return ""
+	}
+	switch field.GORMDataType {
+	case schema.Int, schema.Uint, schema.Float:
+		return "0"
+	case schema.String:
+		return "''"
+	case schema.Bool:
+		return "false"
+	case schema.Time:
+		return "'0001-01-01 00:00:00'"
+	}
+	return ""
+}